@@ -0,0 +1,169 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "time"
+
+    "github.com/veandco/go-sdl2/sdl"
+    "github.com/veandco/go-sdl2/ttf"
+)
+
+// Number of label textures kept, past which the least recently drawn is destroyed, so changing
+// texts like the HUD can't grow the cache forever nor push out the room labels
+const maxCachedLabels = 2048
+
+// Shapes holds circles as the spans and outline points around the origin, computed once per
+// radius, so a circle is drawn with one FillRects and one DrawPoints call.
+type Shapes struct {
+    spans   map[int32][]sdl.Rect
+    outline map[int32][]sdl.Point
+    rects   []sdl.Rect  // Buffer for the translated spans
+    points  []sdl.Point // Buffer for the translated outline
+}
+
+func NewShapes() *Shapes {
+    return &Shapes{spans: make(map[int32][]sdl.Rect), outline: make(map[int32][]sdl.Point)}
+}
+
+// FillCircle fills a circle with the renderer's draw color.
+func (s *Shapes) FillCircle(renderer *sdl.Renderer, x, y, radius int32) {
+    spans, ok := s.spans[radius]
+    if !ok {
+        for h := -radius; h <= radius; h++ {
+            w := int32(math.Sqrt(float64(radius*radius - h*h)))
+            spans = append(spans, sdl.Rect{X: -w, Y: h, W: 2*w + 1, H: 1})
+        }
+        s.spans[radius] = spans
+    }
+    s.rects = s.rects[:0]
+    for _, span := range spans {
+        s.rects = append(s.rects, sdl.Rect{X: x + span.X, Y: y + span.Y, W: span.W, H: 1})
+    }
+    renderer.FillRects(s.rects)
+}
+
+// DrawCircle draws the outline of a circle with the renderer's draw color.
+func (s *Shapes) DrawCircle(renderer *sdl.Renderer, x, y, radius int32) {
+    outline, ok := s.outline[radius]
+    if !ok {
+        seen := make(map[sdl.Point]bool)
+        for angle := 0.0; angle <= 2*math.Pi; angle += 0.5 / float64(radius) {
+            p := sdl.Point{X: int32(float64(radius) * math.Cos(angle)), Y: int32(float64(radius) * math.Sin(angle))}
+            if !seen[p] {
+                seen[p] = true
+                outline = append(outline, p)
+            }
+        }
+        s.outline[radius] = outline
+    }
+    s.points = s.points[:0]
+    for _, p := range outline {
+        s.points = append(s.points, sdl.Point{X: x + p.X, Y: y + p.Y})
+    }
+    renderer.DrawPoints(s.points)
+}
+
+// Labels renders texts once and reuses their textures on later frames.
+type Labels struct {
+    Font     *ttf.Font
+    renderer *sdl.Renderer
+    textures map[string]*label
+    draws    uint64 // Number of texts drawn so far
+}
+
+type label struct {
+    texture *sdl.Texture
+    w, h    int32
+    used    uint64 // Value of draws when last drawn
+}
+
+func NewLabels(renderer *sdl.Renderer, font *ttf.Font) *Labels {
+    return &Labels{Font: font, renderer: renderer, textures: make(map[string]*label)}
+}
+
+// Draw draws black text with its top-left corner, or its center, at (x, y).
+func (l *Labels) Draw(text string, x, y int32, centered bool) {
+    if text == "" {
+        return
+    }
+    cached, ok := l.textures[text]
+    if !ok {
+        if len(l.textures) >= maxCachedLabels {
+            l.evict()
+        }
+        // Render the text to a surface, then to a texture kept for later frames
+        surface, err := l.Font.RenderUTF8Solid(text, sdl.Color{R: 0, G: 0, B: 0, A: 255})
+        if err != nil {
+            fmt.Println("Failed to render text:", err)
+            return
+        }
+        defer surface.Free()
+        texture, err := l.renderer.CreateTextureFromSurface(surface)
+        if err != nil {
+            fmt.Println("Failed to create texture:", err)
+            return
+        }
+        cached = &label{texture: texture, w: surface.W, h: surface.H}
+        l.textures[text] = cached
+    }
+
+    l.draws++
+    cached.used = l.draws
+
+    dstRect := sdl.Rect{X: x, Y: y, W: cached.w, H: cached.h}
+    if centered {
+        dstRect.X -= cached.w / 2
+        dstRect.Y -= cached.h / 2
+    }
+    l.renderer.Copy(cached.texture, nil, &dstRect)
+}
+
+// evict destroys the texture drawn least recently.
+func (l *Labels) evict() {
+    var oldest string
+    for text, cached := range l.textures {
+        if oldest == "" || cached.used < l.textures[oldest].used {
+            oldest = text
+        }
+    }
+    l.textures[oldest].texture.Destroy()
+    delete(l.textures, oldest)
+}
+
+// Clear destroys every cached texture.
+func (l *Labels) Clear() {
+    for text, cached := range l.textures {
+        cached.texture.Destroy()
+        delete(l.textures, text)
+    }
+}
+
+// FrameTimer averages the time spent drawing frames, reporting once per second
+// so the text shown doesn't flicker.
+type FrameTimer struct {
+    frames  int
+    total   time.Duration
+    since   time.Time
+    Average time.Duration // Average drawing time over the last second
+    FPS     int           // Frames shown during the last second
+}
+
+func NewFrameTimer() *FrameTimer {
+    return &FrameTimer{since: time.Now()}
+}
+
+// Add records the time one frame took to draw.
+func (t *FrameTimer) Add(elapsed time.Duration) {
+    t.frames++
+    t.total += elapsed
+    if now := time.Now(); now.Sub(t.since) >= time.Second {
+        t.Average = t.total / time.Duration(t.frames)
+        t.FPS = t.frames
+        t.frames, t.total, t.since = 0, 0, now
+    }
+}
+
+func (t *FrameTimer) String() string {
+    return fmt.Sprintf("%.1f ms/frame, %d FPS", float64(t.Average)/float64(time.Millisecond), t.FPS)
+}
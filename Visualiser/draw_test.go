@@ -0,0 +1,30 @@
+package main
+
+import (
+    "testing"
+    "time"
+)
+
+func TestFrameTimer(t *testing.T) {
+    timer := NewFrameTimer()
+    timer.Add(2 * time.Millisecond)
+    if timer.FPS != 0 || timer.Average != 0 {
+        t.Fatalf("reported %s before a second passed", timer)
+    }
+
+    // Pretend the second is over so the next frame reports both frames so far
+    timer.since = timer.since.Add(-time.Second)
+    timer.Add(4 * time.Millisecond)
+    timer.Add(6 * time.Millisecond)
+    if timer.FPS != 2 || timer.Average != 3*time.Millisecond {
+        t.Fatalf("reported %s, want 2 frames of 3 ms", timer)
+    }
+    if got, want := timer.String(), "3.0 ms/frame, 2 FPS"; got != want {
+        t.Errorf("shown as %q, want %q", got, want)
+    }
+
+    timer.Add(time.Millisecond)
+    if timer.FPS != 2 {
+        t.Errorf("reported %s again before another second passed", timer)
+    }
+}
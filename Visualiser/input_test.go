@@ -0,0 +1,128 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "fmt"
+    "strings"
+    "testing"
+
+    lemin "lem-in/lem-in"
+)
+
+// Output of the solver for a map with two paths, as piped to the visualiser
+const solverOutput = `3
+##start
+s 0 0
+a 1 0
+b 1 1
+##end
+e 2 0
+s-a
+a-e
+s-b
+b-e
+
+L1-a L2-b 
+L1-e L2-e L3-a 
+L3-e 
+`
+
+func TestReadMap(t *testing.T) {
+    graph, first, err := readMap(bufio.NewReader(strings.NewReader(solverOutput)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if graph.Start != "s" || graph.End != "e" || graph.Ants != 3 || len(graph.Rooms) != 4 {
+        t.Errorf("read %d ants and rooms %v from %s to %s", graph.Ants, graph.Rooms, graph.Start, graph.End)
+    }
+    if first != "L1-a L2-b \n" {
+        t.Errorf("moves start with %q, want the first turn", first)
+    }
+
+    if _, _, err := readMap(bufio.NewReader(strings.NewReader("3\n##start\ns 0 0\n"))); err == nil {
+        t.Errorf("read a map without moves")
+    }
+    if _, _, err := readMap(bufio.NewReader(strings.NewReader("3\n##start\ns 0 0\n\nL1-s\n"))); err == nil || !strings.HasPrefix(err.Error(), "Error parsing map") {
+        t.Errorf("got %v for a map without an end room", err)
+    }
+}
+
+// streamAll reads the map from input and returns the turns streamed after it.
+func streamAll(t *testing.T, input string) ([][]lemin.Move, error) {
+    r := bufio.NewReader(strings.NewReader(input))
+    _, first, err := readMap(r)
+    if err != nil {
+        t.Fatal(err)
+    }
+    turns := make(chan StreamedTurn)
+    go streamTurns(first, r, turns)
+    var moves [][]lemin.Move
+    for turn := range turns {
+        if turn.Err != nil {
+            return moves, turn.Err
+        }
+        moves = append(moves, turn.Moves)
+    }
+    return moves, nil
+}
+
+func TestStreamTurns(t *testing.T) {
+    want := "[[{1 a} {2 b}] [{1 e} {2 e} {3 a}] [{3 e}]]"
+    for _, input := range []string{solverOutput, strings.TrimSuffix(solverOutput, "\n")} {
+        turns, err := streamAll(t, input)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if fmt.Sprint(turns) != want {
+            t.Errorf("streamed %v, want %s", turns, want)
+        }
+    }
+
+    turns, err := streamAll(t, strings.Replace(solverOutput, "L3-e", "L3e", 1))
+    if err == nil || len(turns) != 2 {
+        t.Errorf("streamed %v and %v for a bad last turn, want two turns and an error", turns, err)
+    }
+
+    // A turn where only a slow ant is on its way prints an empty line.
+    turns, err = streamAll(t, strings.Replace(solverOutput, "L3-e", "\nL3-e", 1))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := "[[{1 a} {2 b}] [{1 e} {2 e} {3 a}] [] [{3 e}]]"; fmt.Sprint(turns) != want {
+        t.Errorf("streamed %v with an empty turn, want %s", turns, want)
+    }
+}
+
+func TestAddTurn(t *testing.T) {
+    turns, _ := streamAll(t, solverOutput)
+    ants := make(map[string]*Ant)
+    info := newPathInfo("s", "e")
+    for frame, moves := range turns {
+        addTurn(ants, info, "s", frame, moves)
+    }
+    want := map[string][]AntMovementStep{
+        "L1": {{0, "a"}, {1, "e"}},
+        "L2": {{0, "b"}, {1, "e"}},
+        "L3": {{1, "a"}, {2, "e"}},
+    }
+    if len(ants) != len(want) {
+        t.Fatalf("%d ants, want %d", len(ants), len(want))
+    }
+    for id, steps := range want {
+        ant := ants[id]
+        if ant == nil || ant.StartRoom != "s" || fmt.Sprint(ant.Movements) != fmt.Sprint(steps) {
+            t.Errorf("%s: %+v, want moves %v from s", id, ant, steps)
+        }
+    }
+    if ants["L3"].Color != ants["L1"].Color || ants["L2"].Color == ants["L1"].Color {
+        t.Errorf("ants on the same path have different colors")
+    }
+}
+
+func TestEmbeddedFont(t *testing.T) {
+    // TrueType files start with the version 1.0 tag
+    if !bytes.HasPrefix(defaultFont, []byte{0, 1, 0, 0}) {
+        t.Errorf("the embedded font isn't a TrueType font: %d bytes", len(defaultFont))
+    }
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+    "container/list"
+    "fmt"
+
+    lemin "lem-in/lem-in"
+
+    "github.com/veandco/go-sdl2/sdl"
+)
+
+// Colors given to the paths in order, repeated when there are more paths
+var pathPalette = []sdl.Color{
+    {R: 230, G: 25, B: 75, A: 255},
+    {R: 60, G: 180, B: 75, A: 255},
+    {R: 0, G: 130, B: 200, A: 255},
+    {R: 245, G: 130, B: 48, A: 255},
+    {R: 145, G: 30, B: 180, A: 255},
+    {R: 240, G: 50, B: 230, A: 255},
+    {R: 128, G: 128, B: 0, A: 255},
+    {R: 0, G: 128, B: 128, A: 255},
+    {R: 170, G: 110, B: 40, A: 255},
+    {R: 128, G: 0, B: 0, A: 255},
+    {R: 0, G: 0, B: 128, A: 255},
+    {R: 128, G: 128, B: 128, A: 255},
+}
+
+func pathColor(i int) sdl.Color {
+    return pathPalette[i%len(pathPalette)]
+}
+
+// PathInfo holds the paths the ants took, rebuilt from the moves as they are added.
+// Paths only share the start and end rooms, so the first room an ant enters tells its path.
+type PathInfo struct {
+    Paths     *lemin.Paths
+    AntPath   map[string]int    // Path index of each ant, by ant ID
+    EdgePath  map[[2]string]int // Path index of each tunnel on a path, by room names in order
+    RoomPath  map[string]int    // Path index of each room on a path, except the start and end
+    start     string
+    end       string
+    firstRoom map[string]int // Path index by the room it leaves the start room for
+    antSteps  map[string]int // Number of rooms each ant entered so far
+}
+
+func newPathInfo(start, end string) *PathInfo {
+    return &PathInfo{
+        Paths:     new(lemin.Paths),
+        AntPath:   make(map[string]int),
+        EdgePath:  make(map[[2]string]int),
+        RoomPath:  make(map[string]int),
+        start:     start,
+        end:       end,
+        firstRoom: make(map[string]int),
+        antSteps:  make(map[string]int),
+    }
+}
+
+// Add follows the moves of the next turn, extending a path when an ant gets further
+// along it than any ant before.
+func (info *PathInfo) Add(moves []lemin.Move) {
+    paths := info.Paths
+    for _, move := range moves {
+        id := antID(move.Ant)
+        i, ok := info.AntPath[id]
+        if !ok {
+            if i, ok = info.firstRoom[move.Room]; !ok {
+                i = paths.NumPaths
+                info.firstRoom[move.Room] = i
+                path := list.New()
+                path.PushBack(info.start)
+                paths.AllPaths = append(paths.AllPaths, path)
+                paths.Assignment = append(paths.Assignment, 0)
+                paths.NumPaths++
+            }
+            info.AntPath[id] = i
+            paths.Assignment[i]++
+        }
+
+        info.antSteps[id]++
+        path := paths.AllPaths[i]
+        if info.antSteps[id] == path.Len() {
+            info.EdgePath[edgeKey(path.Back().Value.(string), move.Room)] = i
+            if move.Room != info.end {
+                info.RoomPath[move.Room] = i
+            }
+            path.PushBack(move.Room)
+        }
+    }
+}
+
+// edgeColor returns the color of the path using the tunnel between a and b, if any.
+func (info *PathInfo) edgeColor(a, b string) (sdl.Color, bool) {
+    i, ok := info.EdgePath[edgeKey(a, b)]
+    return pathColor(i), ok
+}
+
+// antColor returns the color of the path the ant took.
+func (info *PathInfo) antColor(id string) sdl.Color {
+    return pathColor(info.AntPath[id])
+}
+
+// drawLegend lists each path with its color, length and number of ants from the bottom-left corner.
+func drawLegend(renderer *sdl.Renderer, labels *Labels, info *PathInfo, windowHeight int32) {
+    lineHeight := int32(labels.Font.Height()) + 2
+    y := windowHeight - 10 - lineHeight*int32(info.Paths.NumPaths)
+    for i, path := range info.Paths.AllPaths {
+        color := pathColor(i)
+        renderer.SetDrawColor(color.R, color.G, color.B, color.A)
+        renderer.FillRect(&sdl.Rect{X: 10, Y: y + 3, W: 12, H: lineHeight - 6})
+        text := fmt.Sprintf("Path %d: %d moves, %d ants", i+1, path.Len()-1, info.Paths.Assignment[i])
+        labels.Draw(text, 28, y, false)
+        y += lineHeight
+    }
+}
+
+// edgeKey returns the same key for both directions of a tunnel.
+func edgeKey(a, b string) [2]string {
+    if a > b {
+        a, b = b, a
+    }
+    return [2]string{a, b}
+}
+
+func antID(ant int) string {
+    return fmt.Sprintf("L%d", ant)
+}
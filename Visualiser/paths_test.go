@@ -0,0 +1,69 @@
+package main
+
+import (
+    "testing"
+
+    lemin "lem-in/lem-in"
+)
+
+func TestPathInfo(t *testing.T) {
+    // L1 and L3 take s-a-c-e, L2 takes s-b-e.
+    turns := [][]lemin.Move{
+        {{Ant: 1, Room: "a"}, {Ant: 2, Room: "b"}},
+        {{Ant: 1, Room: "c"}, {Ant: 2, Room: "e"}, {Ant: 3, Room: "a"}},
+        {{Ant: 1, Room: "e"}, {Ant: 3, Room: "c"}},
+        {{Ant: 3, Room: "e"}},
+    }
+    info := newPathInfo("s", "e")
+    info.Add(turns[0])
+    info.Add(turns[1])
+    if path := info.Paths.AllPaths[0]; path.Len() != 3 {
+        t.Errorf("after two turns the first path has %d rooms, want s a c", path.Len())
+    }
+    for _, turn := range turns[2:] {
+        info.Add(turn)
+    }
+
+    wantPaths := [][]string{{"s", "a", "c", "e"}, {"s", "b", "e"}}
+    if info.Paths.NumPaths != len(wantPaths) {
+        t.Fatalf("%d paths, want %d", info.Paths.NumPaths, len(wantPaths))
+    }
+    for i, want := range wantPaths {
+        var rooms []string
+        for e := info.Paths.AllPaths[i].Front(); e != nil; e = e.Next() {
+            rooms = append(rooms, e.Value.(string))
+        }
+        if len(rooms) != len(want) {
+            t.Errorf("path %d: %v, want %v", i, rooms, want)
+            continue
+        }
+        for j := range want {
+            if rooms[j] != want[j] {
+                t.Errorf("path %d: %v, want %v", i, rooms, want)
+                break
+            }
+        }
+    }
+    if info.Paths.Assignment[0] != 2 || info.Paths.Assignment[1] != 1 {
+        t.Errorf("ants per path %v, want [2 1]", info.Paths.Assignment)
+    }
+    if info.AntPath["L3"] != 0 || info.AntPath["L2"] != 1 {
+        t.Errorf("ant paths %v", info.AntPath)
+    }
+    if len(info.RoomPath) != 3 || info.RoomPath["c"] != 0 || info.RoomPath["b"] != 1 {
+        t.Errorf("room paths %v, want a and c on path 0 and b on path 1", info.RoomPath)
+    }
+
+    // Tunnels are colored whichever way they are walked.
+    for _, edge := range [][2]string{{"a", "s"}, {"c", "a"}, {"e", "c"}, {"s", "b"}} {
+        if _, ok := info.edgeColor(edge[0], edge[1]); !ok {
+            t.Errorf("tunnel %s-%s has no color", edge[0], edge[1])
+        }
+    }
+    if _, ok := info.edgeColor("a", "b"); ok {
+        t.Errorf("tunnel a-b isn't on a path but has a color")
+    }
+    if info.antColor("L3") != pathColor(0) || info.antColor("L2") != pathColor(1) {
+        t.Errorf("ants aren't colored like their paths")
+    }
+}
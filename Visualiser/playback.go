@@ -0,0 +1,155 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strconv"
+
+    lemin "lem-in/lem-in"
+
+    "github.com/veandco/go-sdl2/sdl"
+)
+
+// Bounds for the time one turn takes to animate, in milliseconds
+const (
+    MinMovementDuration = 50.0
+    MaxMovementDuration = 5000.0
+)
+
+// Playback tracks which turn is shown and how far the animation towards it has gone.
+type Playback struct {
+    Turn             int     // Number of turns applied
+    TotalTurns       int
+    Progress         float64 // Between 0 and 1, from the previous turn to Turn
+    Playing          bool
+    MovementDuration float64 // milliseconds per turn
+    TurnInput        string  // Digits typed so far for jump-to-turn
+    Following        bool    // More turns are still arriving, so keep playing at the last one
+}
+
+func NewPlayback(totalTurns int) *Playback {
+    return &Playback{TotalTurns: totalTurns, Progress: 1, MovementDuration: 1000}
+}
+
+// Update advances the animation by deltaTime milliseconds.
+func (p *Playback) Update(deltaTime float64) {
+    if p.Progress < 1 {
+        p.Progress = math.Min(1, p.Progress+deltaTime/p.MovementDuration)
+        return
+    }
+    if p.Playing {
+        if p.Turn < p.TotalTurns {
+            p.StepForward()
+        } else if !p.Following {
+            p.Playing = false
+        }
+    }
+}
+
+func (p *Playback) StepForward() {
+    if p.Turn < p.TotalTurns {
+        p.Turn++
+        p.Progress = 0
+    }
+}
+
+func (p *Playback) StepBack() {
+    if p.Turn > 0 {
+        p.Turn--
+        p.Progress = 1
+    }
+}
+
+// JumpTo shows the given turn without animating to it.
+func (p *Playback) JumpTo(turn int) {
+    if turn < 0 {
+        turn = 0
+    }
+    if turn > p.TotalTurns {
+        turn = p.TotalTurns
+    }
+    p.Turn = turn
+    p.Progress = 1
+}
+
+func (p *Playback) Restart() {
+    p.JumpTo(0)
+    p.Playing = false
+}
+
+// HandleKey applies a key press:
+// space play/pause, left/right step, up/down speed, R/Home restart, End last turn,
+// digits then Enter jump to a turn.
+func (p *Playback) HandleKey(sym sdl.Keycode) {
+    switch {
+    case sym == sdl.K_SPACE:
+        p.Playing = !p.Playing
+        if p.Playing && p.Turn == p.TotalTurns {
+            p.JumpTo(0)
+        }
+    case sym == sdl.K_RIGHT:
+        p.Playing = false
+        p.StepForward()
+    case sym == sdl.K_LEFT:
+        p.Playing = false
+        p.StepBack()
+    case sym == sdl.K_UP:
+        p.MovementDuration = math.Max(MinMovementDuration, p.MovementDuration/2)
+    case sym == sdl.K_DOWN:
+        p.MovementDuration = math.Min(MaxMovementDuration, p.MovementDuration*2)
+    case sym == sdl.K_r || sym == sdl.K_HOME:
+        p.Restart()
+    case sym == sdl.K_END:
+        p.Playing = false
+        p.JumpTo(p.TotalTurns)
+    case sym >= sdl.K_0 && sym <= sdl.K_9:
+        p.TurnInput += string(rune('0' + sym - sdl.K_0))
+    case sym == sdl.K_BACKSPACE && p.TurnInput != "":
+        p.TurnInput = p.TurnInput[:len(p.TurnInput)-1]
+    case sym == sdl.K_ESCAPE:
+        p.TurnInput = ""
+    case sym == sdl.K_RETURN || sym == sdl.K_KP_ENTER:
+        if turn, err := strconv.Atoi(p.TurnInput); err == nil {
+            p.Playing = false
+            p.JumpTo(turn)
+        }
+        p.TurnInput = ""
+    }
+}
+
+// HUD returns the status line drawn over the graph.
+func (p *Playback) HUD(finished, totalAnts int) string {
+    hud := fmt.Sprintf("Turn %d/%d   Finished %d/%d   %.0f ms/turn", p.Turn, p.TotalTurns, finished, totalAnts, p.MovementDuration)
+    if !p.Playing {
+        hud += "   [paused]"
+    }
+    if p.Following {
+        hud += "   [following]"
+    }
+    if p.TurnInput != "" {
+        hud += "   Go to turn: " + p.TurnInput
+    }
+    return hud
+}
+
+// roomAt returns the room the ant is in once the given number of turns have been played.
+func (ant *Ant) roomAt(turn int) string {
+    i := sort.Search(len(ant.Movements), func(i int) bool { return ant.Movements[i].Frame >= turn })
+    if i == 0 {
+        return ant.StartRoom
+    }
+    return ant.Movements[i-1].NodeName
+}
+
+// updatePosition places the ant between its rooms of the previous turn and the given one.
+func (ant *Ant) updatePosition(positions map[string]lemin.Point, turn int, progress float64) {
+    ant.To = ant.roomAt(turn)
+    ant.From = ant.To
+    if turn > 0 {
+        ant.From = ant.roomAt(turn - 1)
+    }
+    from, to := positions[ant.From], positions[ant.To]
+    ant.PositionX = from.X + (to.X-from.X)*progress
+    ant.PositionY = from.Y + (to.Y-from.Y)*progress
+}
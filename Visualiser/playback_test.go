@@ -0,0 +1,119 @@
+package main
+
+import (
+    "testing"
+
+    lemin "lem-in/lem-in"
+
+    "github.com/veandco/go-sdl2/sdl"
+)
+
+func TestPlaybackKeys(t *testing.T) {
+    p := NewPlayback(10)
+    p.HandleKey(sdl.K_SPACE)
+    if !p.Playing || p.Turn != 0 {
+        t.Fatalf("space: playing %v at turn %d, want playing from turn 0", p.Playing, p.Turn)
+    }
+    p.HandleKey(sdl.K_RIGHT)
+    p.HandleKey(sdl.K_RIGHT)
+    p.HandleKey(sdl.K_LEFT)
+    if p.Playing || p.Turn != 1 || p.Progress != 1 {
+        t.Errorf("right, right, left: playing %v at turn %d and progress %v, want paused at turn 1", p.Playing, p.Turn, p.Progress)
+    }
+
+    for _, key := range []sdl.Keycode{sdl.K_1, sdl.K_2, sdl.K_BACKSPACE, sdl.K_BACKSPACE, sdl.K_7, sdl.K_RETURN} {
+        p.HandleKey(key)
+    }
+    if p.Turn != 7 || p.TurnInput != "" {
+        t.Errorf("1, 2, backspace twice, 7, enter: turn %d with %q typed, want turn 7", p.Turn, p.TurnInput)
+    }
+    p.HandleKey(sdl.K_9)
+    p.HandleKey(sdl.K_9)
+    p.HandleKey(sdl.K_RETURN)
+    if p.Turn != 10 {
+        t.Errorf("jumping to turn 99 of 10 shows turn %d", p.Turn)
+    }
+    p.HandleKey(sdl.K_HOME)
+    if p.Turn != 0 || p.Playing {
+        t.Errorf("home: playing %v at turn %d, want paused at turn 0", p.Playing, p.Turn)
+    }
+
+    // Playing again from the last turn starts over.
+    p.HandleKey(sdl.K_END)
+    p.HandleKey(sdl.K_SPACE)
+    if p.Turn != 0 || !p.Playing {
+        t.Errorf("end, space: playing %v at turn %d, want playing from turn 0", p.Playing, p.Turn)
+    }
+}
+
+func TestPlaybackSpeed(t *testing.T) {
+    p := NewPlayback(1)
+    for i := 0; i < 10; i++ {
+        p.HandleKey(sdl.K_UP)
+    }
+    if p.MovementDuration != MinMovementDuration {
+        t.Errorf("%v ms per turn after speeding up, want %v", p.MovementDuration, MinMovementDuration)
+    }
+    for i := 0; i < 10; i++ {
+        p.HandleKey(sdl.K_DOWN)
+    }
+    if p.MovementDuration != MaxMovementDuration {
+        t.Errorf("%v ms per turn after slowing down, want %v", p.MovementDuration, MaxMovementDuration)
+    }
+}
+
+func TestPlaybackUpdate(t *testing.T) {
+    p := NewPlayback(2)
+    p.Playing = true
+    p.Update(0) // Starts the first turn
+    p.Update(400)
+    if p.Turn != 1 || p.Progress != 0.4 {
+        t.Errorf("turn %d at %v, want turn 1 at 0.4", p.Turn, p.Progress)
+    }
+    for i := 0; i < 10; i++ {
+        p.Update(500)
+    }
+    if p.Turn != 2 || p.Progress != 1 || p.Playing {
+        t.Errorf("turn %d at %v, playing %v, want to stop at the end of turn 2", p.Turn, p.Progress, p.Playing)
+    }
+
+    // While following, playback waits at the last turn for the next one to arrive
+    p = NewPlayback(1)
+    p.Playing, p.Following = true, true
+    for i := 0; i < 10; i++ {
+        p.Update(500)
+    }
+    if p.Turn != 1 || !p.Playing {
+        t.Errorf("turn %d, playing %v, want to wait at turn 1", p.Turn, p.Playing)
+    }
+    p.TotalTurns++
+    p.Update(500)
+    if p.Turn != 2 {
+        t.Errorf("turn %d, want to go on with turn 2 once it arrives", p.Turn)
+    }
+}
+
+func TestAntPosition(t *testing.T) {
+    positions := map[string]lemin.Point{"s": {X: 0, Y: 0}, "a": {X: 10, Y: 0}, "e": {X: 10, Y: 20}}
+    ant := &Ant{ID: "L1", StartRoom: "s", Movements: []AntMovementStep{{1, "a"}, {3, "e"}}}
+    tests := []struct {
+        turn     int
+        progress float64
+        from, to string
+        x, y     float64
+    }{
+        {0, 1, "s", "s", 0, 0},
+        {1, 1, "s", "s", 0, 0}, // Frame 1 is the second turn
+        {2, 0.5, "s", "a", 5, 0},
+        {3, 1, "a", "a", 10, 0},
+        {4, 0.25, "a", "e", 10, 5},
+        {9, 1, "e", "e", 10, 20},
+    }
+    for _, test := range tests {
+        ant.updatePosition(positions, test.turn, test.progress)
+        if ant.From != test.from || ant.To != test.to || ant.PositionX != test.x || ant.PositionY != test.y {
+            t.Errorf("turn %d at %v: from %s to %s at (%v, %v), want from %s to %s at (%v, %v)", test.turn, test.progress,
+                ant.From, ant.To, ant.PositionX, ant.PositionY, test.from, test.to, test.x, test.y)
+        }
+    }
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "strings"
+
+    lemin "lem-in/lem-in"
+)
+
+// Most turns taken from the stream per frame, so a fast solver can't stall the window
+const maxTurnsPerFrame = 1000
+
+// StreamedTurn is the moves of one turn read from the input, or the error that ended it.
+type StreamedTurn struct {
+    Moves []lemin.Move
+    Err   error
+}
+
+// readMap reads the echoed map up to the first line of moves, which it returns
+// along with the map parsed with the solver's parser.
+func readMap(r *bufio.Reader) (*lemin.Graph, string, error) {
+    var lines []string
+    for {
+        line, err := r.ReadString('\n')
+        // The moves start at the first line beginning with L, which no map line can.
+        if strings.HasPrefix(line, "L") {
+            graph, _, parseErr := lemin.ParseMap(strings.Join(lines, "\n"))
+            if parseErr != nil {
+                return nil, "", fmt.Errorf("Error parsing map: %v", parseErr)
+            }
+            return graph, line, nil
+        }
+        if err == io.EOF {
+            return nil, "", fmt.Errorf("Error reading input: no moves found, expected lem-in output on stdin")
+        }
+        if err != nil {
+            return nil, "", fmt.Errorf("Error reading input: %v", err)
+        }
+        lines = append(lines, strings.TrimRight(line, "\r\n"))
+    }
+}
+
+// streamTurns sends the turns read from r, one per line starting with first, as they
+// arrive, and closes turns at the end of the input or after sending an error.
+func streamTurns(first string, r *bufio.Reader, turns chan<- StreamedTurn) {
+    defer close(turns)
+    line := first
+    for {
+        parsed, err := lemin.ParseMoves(line)
+        if err != nil {
+            turns <- StreamedTurn{Err: fmt.Errorf("Error reading ant movements: %v", err)}
+            return
+        }
+        for _, moves := range parsed {
+            turns <- StreamedTurn{Moves: moves}
+        }
+
+        line, err = r.ReadString('\n')
+        if err == io.EOF && line != "" {
+            continue // Last line without a newline, ReadString returns EOF again next time
+        }
+        if err == io.EOF {
+            return
+        }
+        if err != nil {
+            turns <- StreamedTurn{Err: fmt.Errorf("Error reading ant movements: %v", err)}
+            return
+        }
+    }
+}
+
+// addTurn records the moves of a turn, creating the ants leaving the start room.
+func addTurn(ants map[string]*Ant, pathInfo *PathInfo, start string, frame int, moves []lemin.Move) {
+    pathInfo.Add(moves)
+    for _, move := range moves {
+        id := antID(move.Ant)
+        ant, ok := ants[id]
+        if !ok {
+            ant = &Ant{ID: id, StartRoom: start, Color: pathInfo.antColor(id)}
+            ants[id] = ant
+        }
+        ant.Movements = append(ant.Movements, AntMovementStep{Frame: frame, NodeName: move.Room})
+    }
+}
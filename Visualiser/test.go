@@ -2,33 +2,43 @@ package main
 
 import (
     "bufio"
+    _ "embed"
+    "flag"
     "fmt"
     "math"
     "os"
-    "strings"
     "time"
 
+    lemin "lem-in/lem-in"
+
     "github.com/veandco/go-sdl2/sdl"
     "github.com/veandco/go-sdl2/ttf"
 )
 
-type Node struct {
-    Name  string
-    X, Y  float64
-    Edges []*Node
-}
+// Exit codes
+const (
+    ExitOK      = 0
+    ExitInput   = 1 // The input isn't lem-in output
+    ExitUsage   = 2 // Bad flags or arguments
+    ExitDisplay = 3 // SDL, the window or the font couldn't be set up
+)
+
+// Size of the labels, in points
+const FontSize = 16
+
+// Font used unless --font names another one
+//go:embed Arial.ttf
+var defaultFont []byte
 
 type Ant struct {
-    ID            string
-    Movements     []AntMovementStep
-    PositionX     float64
-    PositionY     float64
-    FromNode      *Node
-    ToNode        *Node
-    Progress      float64 // Between 0 and 1
-    Animating     bool
-    CurrentNode   *Node
-    MovementIndex int // Index in Movements
+    ID        string
+    Movements []AntMovementStep // Sorted by Frame
+    PositionX float64
+    PositionY float64
+    StartRoom string
+    From, To  string    // Rooms the ant moves between in the current turn
+    Color     sdl.Color // Color of the path the ant takes
+    InTransit bool      // Between the start and end rooms, so labelled
 }
 
 type AntMovementStep struct {
@@ -36,330 +46,229 @@ type AntMovementStep struct {
     NodeName string
 }
 
+// Reads lem-in output from stdin: ./lem-in [--stream] map.txt | visualiser [--follow] [--font file.ttf]
 func main() {
-    edges := readEdges("input.txt")
-    graph := buildGraph(edges)
-    assignPositions(graph, 400, 300, 200) // Center at (400,300), radius 200
+    os.Exit(run())
+}
 
-    // Read ant movements
-    antSequences, err := readAntMovements("movements.txt")
+// run shows the visualiser and returns the exit code.
+func run() int {
+    fontPath := flag.String("font", "", "TrueType font for the labels instead of the embedded Arial")
+    follow := flag.Bool("follow", false, "animate turns as they arrive instead of waiting for the whole solution")
+    flag.Parse()
+    if flag.NArg() != 0 {
+        fmt.Println("Usage: ./lem-in [--stream] map.txt | visualiser [--follow] [--font file.ttf]")
+        return ExitUsage
+    }
+
+    input := bufio.NewReader(os.Stdin)
+    lemGraph, first, err := readMap(input)
     if err != nil {
         fmt.Println(err)
-        return
+        return ExitInput
     }
+    positions := lemin.Layout(lemGraph, WorldWidth, WorldHeight, 40)
 
-    // Build ants map
+    // Read ant movements in the background, as the solver prints them
+    turns := make(chan StreamedTurn, maxTurnsPerFrame)
+    go streamTurns(first, input, turns)
+    pathInfo := newPathInfo(lemGraph.Start, lemGraph.End)
     ants := make(map[string]*Ant)
-    for antID := range antSequences {
-        ant := &Ant{
-            ID:            antID,
-            Movements:     antSequences[antID],
-            PositionX:     graph["start"].X,
-            PositionY:     graph["start"].Y,
-            Animating:     false,
-            CurrentNode:   graph["start"],
-            MovementIndex: 0,
+    playback := NewPlayback(0)
+    if *follow {
+        playback.Following, playback.Playing = true, true
+    } else {
+        // Wait for every turn before showing any
+        for turn := range turns {
+            if turn.Err != nil {
+                fmt.Println(turn.Err)
+                return ExitInput
+            }
+            addTurn(ants, pathInfo, lemGraph.Start, playback.TotalTurns, turn.Moves)
+            playback.TotalTurns++
         }
-        ants[antID] = ant
     }
 
     if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
         fmt.Println("Error initializing SDL:", err)
-        return
+        return ExitDisplay
     }
     defer sdl.Quit()
 
     if err := ttf.Init(); err != nil {
         fmt.Println("Error initializing TTF:", err)
-        return
+        return ExitDisplay
     }
     defer ttf.Quit()
 
-    window, err := sdl.CreateWindow("Graph Visualization", sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED, 800, 600, sdl.WINDOW_SHOWN)
+    window, err := sdl.CreateWindow("Graph Visualization", sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED, 800, 600, sdl.WINDOW_SHOWN|sdl.WINDOW_RESIZABLE)
     if err != nil {
         fmt.Println("Error creating window:", err)
-        return
+        return ExitDisplay
     }
     defer window.Destroy()
 
     renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
     if err != nil {
         fmt.Println("Error creating renderer:", err)
-        return
+        return ExitDisplay
     }
     defer renderer.Destroy()
 
-    font, err := ttf.OpenFont("Arial.ttf", 16)
+    font, err := loadFont(*fontPath, FontSize)
     if err != nil {
         fmt.Println("Failed to load font:", err)
-        return
+        return ExitDisplay
     }
     defer font.Close()
-
-    const MovementDuration = 1000.0 // milliseconds per movement (animation duration)
+    labels := NewLabels(renderer, font)
+    defer labels.Clear()
+    shapes := NewShapes()
+    timer := NewFrameTimer()
 
     running := true
-    advanceFrame := false
-    animating := false
-    frameIndex := 0
     lastUpdateTime := time.Now()
 
-    // Prepare frames from movements.txt
-    frames := parseFrames(antSequences)
+    view := NewView(800, 600)
 
     for running {
+        // Take the turns that arrived since the last frame
+    drain:
+        for i := 0; playback.Following && i < maxTurnsPerFrame; i++ {
+            select {
+            case turn, ok := <-turns:
+                if !ok {
+                    playback.Following = false
+                    break drain
+                }
+                if turn.Err != nil {
+                    fmt.Println(turn.Err)
+                    return ExitInput
+                }
+                addTurn(ants, pathInfo, lemGraph.Start, playback.TotalTurns, turn.Moves)
+                playback.TotalTurns++
+            default:
+                break drain
+            }
+        }
+
         // Handle events
+        screen := view.Transform(positions)
         for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
             switch e := event.(type) {
             case *sdl.QuitEvent:
                 running = false
             case *sdl.KeyboardEvent:
-                if e.Type == sdl.KEYDOWN && e.Keysym.Sym == sdl.K_RIGHT {
-                    if !animating && frameIndex < len(frames) {
-                        advanceFrame = true
-                    }
+                if e.Type == sdl.KEYDOWN {
+                    playback.HandleKey(e.Keysym.Sym)
                 }
+            default:
+                view.HandleEvent(event, screen)
             }
         }
+        screen = view.Transform(positions)
 
         currentTime := time.Now()
         deltaTime := currentTime.Sub(lastUpdateTime).Seconds() * 1000 // in milliseconds
         lastUpdateTime = currentTime
+        playback.Update(deltaTime)
 
-        if advanceFrame && frameIndex < len(frames) {
-            // Start animating ants for the next frame
-            frameMovements := frames[frameIndex]
-            for antID, nodeName := range frameMovements {
-                ant := ants[antID]
-                if ant != nil {
-                    ant.Animating = true
-                    ant.Progress = 0.0
-                    ant.FromNode = ant.CurrentNode
-                    ant.ToNode = graph[nodeName]
-                    ant.MovementIndex++
-                }
-            }
-            animating = true
-            advanceFrame = false
-            frameIndex++
-        }
-
-        if animating {
-            allAntsFinished := true
-            for _, ant := range ants {
-                if ant.Animating {
-                    ant.Progress += deltaTime / MovementDuration
-                    if ant.Progress >= 1.0 {
-                        ant.PositionX = ant.ToNode.X
-                        ant.PositionY = ant.ToNode.Y
-                        ant.CurrentNode = ant.ToNode // Update the current node
-                        ant.Animating = false
-                    } else {
-                        ant.PositionX = ant.FromNode.X + (ant.ToNode.X - ant.FromNode.X)*ant.Progress
-                        ant.PositionY = ant.FromNode.Y + (ant.ToNode.Y - ant.FromNode.Y)*ant.Progress
-                    }
-                    allAntsFinished = false
-                }
-            }
-            if allAntsFinished {
-                animating = false
-            }
-        }
-
-        // Update positions of ants that are not animating
+        // Place every ant for the current turn and count the ones that arrived
+        finished := 0
         for _, ant := range ants {
-            if !ant.Animating {
-                ant.PositionX = ant.CurrentNode.X
-                ant.PositionY = ant.CurrentNode.Y
+            ant.updatePosition(screen, playback.Turn, playback.Progress)
+            if ant.To == lemGraph.End {
+                finished++
             }
+            ant.InTransit = playback.Progress < 1 || (ant.To != lemGraph.Start && ant.To != lemGraph.End)
         }
 
         // Draw the graph with the current ant positions
-        drawGraph(renderer, font, graph, ants)
+        hud := playback.HUD(finished, len(ants)) + "   " + timer.String()
+        drawGraph(renderer, labels, shapes, lemGraph, screen, view.RoomRadius(), ants, pathInfo, hud)
+        if view.Selected != "" {
+            drawInspector(renderer, labels, inspectRoom(lemGraph, pathInfo, ants, playback.Turn, view.Selected))
+        }
+        renderer.Present()
+        timer.Add(time.Since(currentTime))
 
         // Delay to control frame rate
         sdl.Delay(16) // Approximately 60 FPS
     }
+    return ExitOK
 }
 
-func parseFrames(antSequences map[string][]AntMovementStep) []map[string]string {
-    // Find the total number of frames
-    maxFrame := 0
-    for _, steps := range antSequences {
-        for _, step := range steps {
-            if step.Frame > maxFrame {
-                maxFrame = step.Frame
-            }
-        }
-    }
-
-    // Initialize frames
-    frames := make([]map[string]string, maxFrame+1)
-    for i := 0; i <= maxFrame; i++ {
-        frames[i] = make(map[string]string)
-    }
-
-    // Populate frames with ant movements
-    for antID, steps := range antSequences {
-        for _, step := range steps {
-            frames[step.Frame][antID] = step.NodeName
-        }
-    }
-
-    return frames
-}
-
-func readEdges(filename string) [][2]string {
-    file, err := os.Open(filename)
-    if err != nil {
-        fmt.Println("Error opening file:", err)
-        return nil
+// loadFont opens the font file at path, or the embedded font if path is empty.
+func loadFont(path string, size int) (*ttf.Font, error) {
+    if path != "" {
+        return ttf.OpenFont(path, size)
     }
-    defer file.Close()
-
-    var edges [][2]string
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        line := scanner.Text()
-        nodes := strings.Split(line, "-")
-        if len(nodes) == 2 {
-            edges = append(edges, [2]string{nodes[0], nodes[1]})
-        }
-    }
-
-    if err := scanner.Err(); err != nil {
-        fmt.Println("Error reading file:", err)
-    }
-
-    return edges
-}
-
-func readAntMovements(filename string) (map[string][]AntMovementStep, error) {
-    file, err := os.Open(filename)
+    rw, err := sdl.RWFromMem(defaultFont)
     if err != nil {
-        return nil, fmt.Errorf("Error opening ant movements file: %v", err)
-    }
-    defer file.Close()
-
-    antSequences := make(map[string][]AntMovementStep)
-    scanner := bufio.NewScanner(file)
-    frame := 0
-
-    for scanner.Scan() {
-        line := scanner.Text()
-        tokens := strings.Fields(line)
-        for _, token := range tokens {
-            parts := strings.Split(token, "-")
-            if len(parts) == 2 {
-                antID := parts[0]
-                nodeName := parts[1]
-                antSequences[antID] = append(antSequences[antID], AntMovementStep{
-                    Frame:    frame,
-                    NodeName: nodeName,
-                })
-            }
-        }
-        frame++
-    }
-
-    if err := scanner.Err(); err != nil {
-        return nil, fmt.Errorf("Error reading ant movements file: %v", err)
+        return nil, err
     }
-
-    return antSequences, nil
+    return ttf.OpenFontRW(rw, 1, size)
 }
 
-func buildGraph(edges [][2]string) map[string]*Node {
-    nodes := make(map[string]*Node)
-
-    for _, edge := range edges {
-        fromName, toName := edge[0], edge[1]
-
-        fromNode, exists := nodes[fromName]
-        if !exists {
-            fromNode = &Node{Name: fromName}
-            nodes[fromName] = fromNode
-        }
-
-        toNode, exists := nodes[toName]
-        if !exists {
-            toNode = &Node{Name: toName}
-            nodes[toName] = toNode
-        }
-
-        // Directed edge from 'fromNode' to 'toNode'
-        fromNode.Edges = append(fromNode.Edges, toNode)
-    }
-
-    return nodes
-}
-
-func assignPositions(nodes map[string]*Node, centerX, centerY, radius float64) {
-    totalNodes := len(nodes)
-    angleIncrement := 2 * math.Pi / float64(totalNodes)
-    i := 0
-
-    for _, node := range nodes {
-        angle := angleIncrement * float64(i)
-        node.X = centerX + radius*math.Cos(angle)
-        node.Y = centerY + radius*math.Sin(angle)
-        i++
-    }
-}
-
-func drawGraph(renderer *sdl.Renderer, font *ttf.Font, nodes map[string]*Node, ants map[string]*Ant) {
+func drawGraph(renderer *sdl.Renderer, labels *Labels, shapes *Shapes, graph *lemin.Graph, positions map[string]lemin.Point, radius int32, ants map[string]*Ant, pathInfo *PathInfo, hud string) {
     renderer.SetDrawColor(255, 255, 255, 255) // White background
     renderer.Clear()
 
-    // Draw edges
-    for _, node := range nodes {
-        for _, neighbor := range node.Edges {
-            drawArrow(renderer, node.X, node.Y, neighbor.X, neighbor.Y)
+    // Draw tunnels once each, highlighting the ones on a path in its color
+    for name, room := range graph.Rooms {
+        for neighbor := range room.Edges {
+            if name > neighbor {
+                continue
+            }
+            from, to := positions[name], positions[neighbor]
+            color, onPath := pathInfo.edgeColor(name, neighbor)
+            if !onPath {
+                color = sdl.Color{R: 0, G: 0, B: 0, A: 255}
+            }
+            renderer.SetDrawColor(color.R, color.G, color.B, color.A)
+            renderer.DrawLine(int32(from.X), int32(from.Y), int32(to.X), int32(to.Y))
+            if onPath {
+                renderer.DrawLine(int32(from.X)+1, int32(from.Y)+1, int32(to.X)+1, int32(to.Y)+1)
+                renderer.DrawLine(int32(from.X)-1, int32(from.Y)-1, int32(to.X)-1, int32(to.Y)-1)
+            }
         }
     }
 
-    // Draw nodes and labels
-    for _, node := range nodes {
-        drawCircle(renderer, int32(node.X), int32(node.Y), 20)
-        drawLabel(renderer, font, node)
-    }
-
-    // Draw ants
+    // Show the direction of the tunnels ants move along this turn
     for _, ant := range ants {
-        drawAnt(renderer, int32(ant.PositionX), int32(ant.PositionY))
+        if ant.From != ant.To {
+            drawArrowhead(renderer, positions[ant.From], positions[ant.To], ant.Color)
+        }
     }
 
-    renderer.Present()
-}
+    // Draw rooms and labels
+    for name, p := range positions {
+        drawCircle(renderer, shapes, int32(p.X), int32(p.Y), radius)
+        labels.Draw(name, int32(p.X), int32(p.Y), true)
+    }
 
-func drawCircle(renderer *sdl.Renderer, x0, y0, radius int32) {
-    // Draw filled circle (light yellow)
-    renderer.SetDrawColor(255, 255, 224, 255) // Light yellow fill color
-    for w := -radius; w <= radius; w++ {
-        for h := -radius; h <= radius; h++ {
-            if w*w+h*h <= radius*radius {
-                renderer.DrawPoint(x0+w, y0+h)
-            }
+    // Draw ants in the color of their path, with their ID
+    for _, ant := range ants {
+        drawAnt(renderer, shapes, int32(ant.PositionX), int32(ant.PositionY), ant.Color)
+        if ant.InTransit {
+            labels.Draw(ant.ID, int32(ant.PositionX)+7, int32(ant.PositionY)-18, false)
         }
     }
 
-    // Draw circle outline (black)
-    renderer.SetDrawColor(0, 0, 0, 255) // Black outline color
-    for angle := 0.0; angle <= 2*math.Pi; angle += 0.01 {
-        x := x0 + int32(float64(radius)*math.Cos(angle))
-        y := y0 + int32(float64(radius)*math.Sin(angle))
-        renderer.DrawPoint(x, y)
-    }
+    labels.Draw(hud, 10, 10, false)
+    _, windowHeight, _ := renderer.GetOutputSize()
+    drawLegend(renderer, labels, pathInfo, windowHeight)
 }
 
-func drawArrow(renderer *sdl.Renderer, x1, y1, x2, y2 float64) {
-    renderer.SetDrawColor(0, 0, 0, 255) // Black color for edges
+// drawArrowhead draws an arrowhead halfway along the tunnel, pointing from one room to the other.
+func drawArrowhead(renderer *sdl.Renderer, from, to lemin.Point, color sdl.Color) {
+    renderer.SetDrawColor(color.R, color.G, color.B, color.A)
 
-    // Draw the line
-    renderer.DrawLine(int32(x1), int32(y1), int32(x2), int32(y2))
+    // Point the arrow at the middle of the tunnel, where the room circles can't hide it
+    x2, y2 := (from.X+to.X)/2, (from.Y+to.Y)/2
 
     // Calculate the angle of the line
-    angle := math.Atan2(y2 - y1, x2 - x1)
+    angle := math.Atan2(to.Y-from.Y, to.X-from.X)
 
     // Arrowhead size
     arrowSize := 10.0
@@ -375,48 +284,14 @@ func drawArrow(renderer *sdl.Renderer, x1, y1, x2, y2 float64) {
     renderer.DrawLine(int32(x2), int32(y2), int32(x4), int32(y4))
 }
 
-func drawLabel(renderer *sdl.Renderer, font *ttf.Font, node *Node) {
-    // Render the text to a surface
-    surface, err := font.RenderUTF8Solid(node.Name, sdl.Color{R: 0, G: 0, B: 0, A: 255})
-    if err != nil {
-        fmt.Println("Failed to render text:", err)
-        return
-    }
-    defer surface.Free()
-
-    // Create a texture from the surface
-    texture, err := renderer.CreateTextureFromSurface(surface)
-    if err != nil {
-        fmt.Println("Failed to create texture:", err)
-        return
-    }
-    defer texture.Destroy()
-
-    // Get the dimensions of the text
-    textWidth := surface.W
-    textHeight := surface.H
-
-    // Define the rectangle where the text will be drawn
-    dstRect := sdl.Rect{
-        X: int32(node.X) - textWidth/2,
-        Y: int32(node.Y) - textHeight/2,
-        W: textWidth,
-        H: textHeight,
-    }
-
-    // Copy the texture to the renderer
-    renderer.Copy(texture, nil, &dstRect)
+func drawCircle(renderer *sdl.Renderer, shapes *Shapes, x, y, radius int32) {
+    renderer.SetDrawColor(255, 255, 224, 255) // Light yellow fill color
+    shapes.FillCircle(renderer, x, y, radius)
+    renderer.SetDrawColor(0, 0, 0, 255) // Black outline color
+    shapes.DrawCircle(renderer, x, y, radius)
 }
 
-func drawAnt(renderer *sdl.Renderer, x, y int32) {
-    renderer.SetDrawColor(255, 0, 0, 255) // Red color for ants
-    radius := int32(5)                    // Ant size
-
-    for w := -radius; w <= radius; w++ {
-        for h := -radius; h <= radius; h++ {
-            if w*w+h*h <= radius*radius {
-                renderer.DrawPoint(x+w, y+h)
-            }
-        }
-    }
+func drawAnt(renderer *sdl.Renderer, shapes *Shapes, x, y int32, color sdl.Color) {
+    renderer.SetDrawColor(color.R, color.G, color.B, color.A)
+    shapes.FillCircle(renderer, x, y, 5) // Ant size
 }
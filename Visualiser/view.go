@@ -0,0 +1,176 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strings"
+
+    lemin "lem-in/lem-in"
+
+    "github.com/veandco/go-sdl2/sdl"
+)
+
+// Size of the area the rooms are laid out in, before zooming
+const (
+    WorldWidth  = 800.0
+    WorldHeight = 600.0
+)
+
+// Bounds for the zoom factor
+const (
+    MinZoom = 0.1
+    MaxZoom = 20.0
+)
+
+// Radius of the rooms at zoom 1, in pixels
+const RoomRadius = 20.0
+
+// View maps the laid out rooms to the window, following zoom and pan,
+// and remembers the room picked with the mouse.
+type View struct {
+    Zoom             float64
+    OffsetX, OffsetY float64 // Window position of the layout's origin
+    Selected         string  // Room clicked last, if any
+    dragging         bool
+    dragged          bool // The mouse moved while the button was down, so it's not a click
+}
+
+func NewView(width, height int32) *View {
+    view := &View{}
+    view.Fit(width, height)
+    return view
+}
+
+// Fit zooms and centers the layout to fill a window of the given size.
+func (v *View) Fit(width, height int32) {
+    v.Zoom = math.Min(float64(width)/WorldWidth, float64(height)/WorldHeight)
+    v.OffsetX = (float64(width) - WorldWidth*v.Zoom) / 2
+    v.OffsetY = (float64(height) - WorldHeight*v.Zoom) / 2
+}
+
+// Transform returns the window positions of the rooms.
+func (v *View) Transform(positions map[string]lemin.Point) map[string]lemin.Point {
+    screen := make(map[string]lemin.Point, len(positions))
+    for name, p := range positions {
+        screen[name] = lemin.Point{X: v.OffsetX + p.X*v.Zoom, Y: v.OffsetY + p.Y*v.Zoom}
+    }
+    return screen
+}
+
+// RoomRadius returns the radius rooms are drawn with at the current zoom.
+func (v *View) RoomRadius() int32 {
+    return int32(math.Max(3, RoomRadius*v.Zoom))
+}
+
+// zoomAt multiplies the zoom by factor, keeping the point under (x, y) in place.
+func (v *View) zoomAt(x, y, factor float64) {
+    zoom := math.Max(MinZoom, math.Min(MaxZoom, v.Zoom*factor))
+    factor = zoom / v.Zoom
+    v.OffsetX = x - (x-v.OffsetX)*factor
+    v.OffsetY = y - (y-v.OffsetY)*factor
+    v.Zoom = zoom
+}
+
+// HandleEvent applies mouse and window events:
+// wheel zooms around the pointer, left drag pans, left click selects the room under
+// the pointer or clears the selection. screen holds the current window positions of the rooms.
+func (v *View) HandleEvent(event sdl.Event, screen map[string]lemin.Point) {
+    switch e := event.(type) {
+    case *sdl.WindowEvent:
+        if e.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
+            v.Fit(e.Data1, e.Data2)
+        }
+    case *sdl.MouseWheelEvent:
+        scroll := e.Y
+        if e.Direction == sdl.MOUSEWHEEL_FLIPPED {
+            scroll = -scroll
+        }
+        x, y, _ := sdl.GetMouseState()
+        v.zoomAt(float64(x), float64(y), math.Pow(1.2, float64(scroll)))
+    case *sdl.MouseButtonEvent:
+        if e.Button != sdl.BUTTON_LEFT {
+            return
+        }
+        if e.Type == sdl.MOUSEBUTTONDOWN {
+            v.dragging, v.dragged = true, false
+            return
+        }
+        if v.dragging && !v.dragged {
+            v.Selected = roomUnder(screen, float64(e.X), float64(e.Y), float64(v.RoomRadius()))
+        }
+        v.dragging = false
+    case *sdl.MouseMotionEvent:
+        if v.dragging && e.State&sdl.ButtonLMask() != 0 {
+            v.OffsetX += float64(e.XRel)
+            v.OffsetY += float64(e.YRel)
+            v.dragged = true
+        }
+    }
+}
+
+// roomUnder returns the room whose circle contains (x, y), or "" if none does.
+func roomUnder(screen map[string]lemin.Point, x, y, radius float64) string {
+    found, best := "", radius*radius
+    for name, p := range screen {
+        if d := (p.X-x)*(p.X-x) + (p.Y-y)*(p.Y-y); d <= best {
+            found, best = name, d
+        }
+    }
+    return found
+}
+
+// inspectRoom describes a room: its name, declared coordinates, degree, the path using
+// it and the ants in it once the given number of turns have been played.
+func inspectRoom(graph *lemin.Graph, pathInfo *PathInfo, ants map[string]*Ant, turn int, name string) []string {
+    lines := []string{"Room " + name}
+    if coord, ok := graph.Coords[name]; ok {
+        lines = append(lines, fmt.Sprintf("Coordinates: %d, %d", coord.X, coord.Y))
+    }
+    lines = append(lines, fmt.Sprintf("Degree: %d", len(graph.Rooms[name].Edges)))
+
+    if path, ok := pathInfo.RoomPath[name]; name == graph.Start || name == graph.End {
+        lines = append(lines, "Path: all")
+    } else if ok {
+        lines = append(lines, fmt.Sprintf("Path: %d", path+1))
+    } else {
+        lines = append(lines, "Path: none")
+    }
+
+    var inRoom []string
+    for id, ant := range ants {
+        if ant.roomAt(turn) == name {
+            inRoom = append(inRoom, id)
+        }
+    }
+    switch {
+    case len(inRoom) == 0:
+        lines = append(lines, "Ant: none")
+    case len(inRoom) > 5:
+        lines = append(lines, fmt.Sprintf("Ants: %d", len(inRoom)))
+    default:
+        sort.Strings(inRoom)
+        lines = append(lines, "Ant: "+strings.Join(inRoom, " "))
+    }
+    return lines
+}
+
+// drawInspector draws the lines describing the selected room on a panel in the top-right corner.
+func drawInspector(renderer *sdl.Renderer, labels *Labels, lines []string) {
+    windowWidth, _, _ := renderer.GetOutputSize()
+    lineHeight := int32(labels.Font.Height()) + 2
+    width := int32(0)
+    for _, line := range lines {
+        if w, _, err := labels.Font.SizeUTF8(line); err == nil && int32(w) > width {
+            width = int32(w)
+        }
+    }
+    panel := sdl.Rect{X: windowWidth - width - 20, Y: 10, W: width + 10, H: lineHeight*int32(len(lines)) + 6}
+    renderer.SetDrawColor(240, 240, 240, 255)
+    renderer.FillRect(&panel)
+    renderer.SetDrawColor(0, 0, 0, 255)
+    renderer.DrawRect(&panel)
+    for i, line := range lines {
+        labels.Draw(line, panel.X+5, panel.Y+3+lineHeight*int32(i), false)
+    }
+}
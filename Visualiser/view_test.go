@@ -0,0 +1,81 @@
+package main
+
+import (
+    "math"
+    "reflect"
+    "testing"
+
+    lemin "lem-in/lem-in"
+)
+
+func TestViewZoomKeepsThePointerInPlace(t *testing.T) {
+    v := NewView(1600, 600) // Twice as wide as the layout, so it's centered at zoom 1
+    if v.Zoom != 1 || v.OffsetX != 400 || v.OffsetY != 0 {
+        t.Fatalf("zoom %v at (%v, %v), want 1 at (400, 0)", v.Zoom, v.OffsetX, v.OffsetY)
+    }
+
+    room := map[string]lemin.Point{"a": {X: 100, Y: 50}}
+    before := v.Transform(room)["a"]
+    v.zoomAt(before.X, before.Y, 3)
+    if after := v.Transform(room)["a"]; math.Abs(after.X-before.X) > 1e-9 || math.Abs(after.Y-before.Y) > 1e-9 {
+        t.Errorf("room under the pointer moved from %v to %v", before, after)
+    }
+    if v.Zoom != 3 || v.RoomRadius() != 60 {
+        t.Errorf("zoom %v with rooms of radius %d, want 3 and 60", v.Zoom, v.RoomRadius())
+    }
+
+    v.zoomAt(0, 0, 1000)
+    if v.Zoom != MaxZoom {
+        t.Errorf("zoom %v, want at most %v", v.Zoom, MaxZoom)
+    }
+    v.zoomAt(0, 0, 1e-9)
+    if v.Zoom != MinZoom || v.RoomRadius() != 3 {
+        t.Errorf("zoom %v with rooms of radius %d, want %v and 3", v.Zoom, v.RoomRadius(), MinZoom)
+    }
+}
+
+func TestRoomUnder(t *testing.T) {
+    screen := map[string]lemin.Point{"a": {X: 0, Y: 0}, "b": {X: 30, Y: 0}}
+    tests := []struct {
+        x, y float64
+        room string
+    }{
+        {0, 0, "a"},
+        {12, 0, "a"},
+        {18, 0, "b"}, // In both circles, closer to b
+        {15, 25, ""},
+        {-21, 0, ""},
+    }
+    for _, test := range tests {
+        if room := roomUnder(screen, test.x, test.y, 20); room != test.room {
+            t.Errorf("(%v, %v): %q, want %q", test.x, test.y, room, test.room)
+        }
+    }
+}
+
+func TestInspectRoom(t *testing.T) {
+    graph, _, err := lemin.ParseMap("2\n##start\ns 0 0\na 1 0\nb 1 1\n##end\ne 2 0\ns-a\na-e\ns-b\nb-e\na-b\n")
+    if err != nil {
+        t.Fatal(err)
+    }
+    info := newPathInfo("s", "e")
+    info.Add([]lemin.Move{{Ant: 1, Room: "a"}})
+    info.Add([]lemin.Move{{Ant: 1, Room: "e"}, {Ant: 2, Room: "a"}})
+    ants := map[string]*Ant{
+        "L1": {ID: "L1", StartRoom: "s", Movements: []AntMovementStep{{0, "a"}, {1, "e"}}},
+        "L2": {ID: "L2", StartRoom: "s", Movements: []AntMovementStep{{1, "a"}, {2, "e"}}},
+    }
+
+    want := []string{"Room a", "Coordinates: 1, 0", "Degree: 3", "Path: 1", "Ant: L1"}
+    if lines := inspectRoom(graph, info, ants, 1, "a"); !reflect.DeepEqual(lines, want) {
+        t.Errorf("a after a turn: %q, want %q", lines, want)
+    }
+    want = []string{"Room b", "Coordinates: 1, 1", "Degree: 3", "Path: none", "Ant: none"}
+    if lines := inspectRoom(graph, info, ants, 1, "b"); !reflect.DeepEqual(lines, want) {
+        t.Errorf("b: %q, want %q", lines, want)
+    }
+    want = []string{"Room s", "Coordinates: 0, 0", "Degree: 2", "Path: all", "Ant: L2"}
+    if lines := inspectRoom(graph, info, ants, 1, "s"); !reflect.DeepEqual(lines, want) {
+        t.Errorf("s after a turn: %q, want %q", lines, want)
+    }
+}
@@ -0,0 +1,173 @@
+package lemin
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Bottleneck describes the cut limiting the number of vertex-disjoint paths.
+type Bottleneck struct {
+	MaxPaths     int
+	CutRooms     []string
+	DirectTunnel bool // The start and end rooms are linked directly, which no room can cut
+}
+
+// RunAnalyze implements the `analyze` command.
+func RunAnalyze(args []string) {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	ants := flags.Int("ants", 0, "number of ants to evaluate turns for (default: the map's)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: program analyze [--ants=N] input_file")
+		os.Exit(1)
+	}
+
+	graph, _, err := ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if *ants > 0 {
+		graph.Ants = *ants
+	}
+
+	bottleneck := FindBottleneck(graph)
+	fmt.Printf("Vertex-disjoint paths: %d\n", bottleneck.MaxPaths)
+	if bottleneck.MaxPaths == 0 {
+		return
+	}
+	if bottleneck.DirectTunnel {
+		fmt.Printf("The tunnel %s-%s is part of the minimum cut\n", graph.Start, graph.End)
+	}
+	fmt.Printf("Minimum vertex cut: %d rooms\n", len(bottleneck.CutRooms))
+
+	base := Solve(graph)
+	fmt.Printf("Turns for %d ants: %d\n", graph.Ants, base.Turns())
+	fmt.Printf("%-20s %18s %8s\n", "Room", "Turns if duplicated", "Change")
+	for _, room := range bottleneck.CutRooms {
+		clone := CloneGraph(graph)
+		DuplicateRoom(clone, room)
+		paths := Solve(clone)
+		fmt.Printf("%-20s %18d %+8d\n", room, paths.Turns(), paths.Turns()-base.Turns())
+	}
+}
+
+// FindBottleneck computes the maximum number of vertex-disjoint start->end paths and
+// the minimum set of rooms limiting it, using max-flow on the graph with every room
+// split into an in and an out node.
+func FindBottleneck(graph *Graph) *Bottleneck {
+	names := sortedRooms(graph)
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+	in := func(name string) int { return 2 * index[name] }
+	out := func(name string) int { return 2*index[name] + 1 }
+
+	network := newFlowNetwork(2 * len(names))
+	for _, name := range names {
+		capacity := 1
+		if name == graph.Start || name == graph.End {
+			capacity = Infinity
+		}
+		network.addEdge(in(name), out(name), capacity)
+		// Tunnels can't be cut, only rooms, except a direct start-end tunnel which carries one path.
+		for neighbor := range graph.Rooms[name].Edges {
+			if name == graph.Start && neighbor == graph.End {
+				network.addEdge(out(name), in(neighbor), 1)
+			} else {
+				network.addEdge(out(name), in(neighbor), Infinity)
+			}
+		}
+	}
+
+	source, sink := out(graph.Start), in(graph.End)
+	bottleneck := new(Bottleneck)
+	for network.augment(source, sink) {
+		bottleneck.MaxPaths++
+	}
+
+	reached := network.reachable(source)
+	for _, name := range names {
+		if reached[in(name)] && !reached[out(name)] {
+			bottleneck.CutRooms = append(bottleneck.CutRooms, name)
+		}
+	}
+	_, bottleneck.DirectTunnel = graph.Rooms[graph.Start].Edges[graph.End]
+	return bottleneck
+}
+
+// flowNetwork is a residual graph stored as adjacency lists of edge indices.
+// Edge i and i^1 are each other's reverse.
+type flowNetwork struct {
+	head     [][]int
+	to       []int
+	capacity []int
+}
+
+func newFlowNetwork(size int) *flowNetwork {
+	return &flowNetwork{head: make([][]int, size)}
+}
+
+func (network *flowNetwork) addEdge(from, to, capacity int) {
+	network.head[from] = append(network.head[from], len(network.to))
+	network.to = append(network.to, to)
+	network.capacity = append(network.capacity, capacity)
+	network.head[to] = append(network.head[to], len(network.to))
+	network.to = append(network.to, from)
+	network.capacity = append(network.capacity, 0)
+}
+
+// augment pushes flow along one shortest augmenting path and reports whether it found one.
+func (network *flowNetwork) augment(source, sink int) bool {
+	via := make([]int, len(network.head))
+	for i := range via {
+		via[i] = -1
+	}
+	queue := []int{source}
+	for len(queue) > 0 && via[sink] < 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, e := range network.head[node] {
+			next := network.to[e]
+			if network.capacity[e] > 0 && via[next] < 0 && next != source {
+				via[next] = e
+				queue = append(queue, next)
+			}
+		}
+	}
+	if via[sink] < 0 {
+		return false
+	}
+
+	flow := Infinity
+	for node := sink; node != source; node = network.to[via[node]^1] {
+		if network.capacity[via[node]] < flow {
+			flow = network.capacity[via[node]]
+		}
+	}
+	for node := sink; node != source; node = network.to[via[node]^1] {
+		network.capacity[via[node]] -= flow
+		network.capacity[via[node]^1] += flow
+	}
+	return true
+}
+
+// reachable returns the nodes reachable from source in the residual graph.
+func (network *flowNetwork) reachable(source int) []bool {
+	seen := make([]bool, len(network.head))
+	seen[source] = true
+	queue := []int{source}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, e := range network.head[node] {
+			if next := network.to[e]; network.capacity[e] > 0 && !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,42 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindBottleneck(t *testing.T) {
+	tests := []struct {
+		file     string
+		maxPaths int
+		cut      []string
+		direct   bool
+	}{
+		{"example00.txt", 1, []string{"2"}, false},
+		{"example01.txt", 3, []string{"0", "h", "t"}, false},
+		{"example02.txt", 2, []string{"1"}, true}, // 0-3 links the start and end rooms
+		{"example03.txt", 1, []string{"4"}, false},
+		{"example04.txt", 2, []string{"gilfoyle", "jimYoung"}, false},
+		{"example05.txt", 4, []string{"A0", "B0", "C0", "G0"}, false},
+	}
+	for _, test := range tests {
+		graph, _, err := ReadFile("../lemin_test/audit/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bottleneck := FindBottleneck(graph)
+		if bottleneck.MaxPaths != test.maxPaths || !reflect.DeepEqual(bottleneck.CutRooms, test.cut) || bottleneck.DirectTunnel != test.direct {
+			t.Errorf("%s: %+v, want %d paths cut by %v", test.file, *bottleneck, test.maxPaths, test.cut)
+		}
+	}
+}
+
+func TestFindBottleneckWithoutPath(t *testing.T) {
+	graph, _, err := ParseMap("1\n##start\ns 0 0\na 1 0\nb 1 1\n##end\ne 2 0\ns-a\nb-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bottleneck := FindBottleneck(graph); bottleneck.MaxPaths != 0 || len(bottleneck.CutRooms) != 0 {
+		t.Errorf("%+v, want no path and no cut", *bottleneck)
+	}
+}
@@ -0,0 +1,64 @@
+package lemin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ant attributes, written in the input as `##slow 3` or `##priority 1-5` for a range of ants
+const (
+	AttributeSlow     = "slow"     // The ant takes two turns to go through a tunnel
+	AttributePriority = "priority" // The ant must arrive before every ant without priority
+)
+
+// isAntAttribute reports whether a line is an ant attribute directive.
+func isAntAttribute(line string) bool {
+	return strings.HasPrefix(line, "##"+AttributeSlow+" ") || strings.HasPrefix(line, "##"+AttributePriority+" ")
+}
+
+// parseAntAttribute adds the ants of an attribute line to the graph, whose number of
+// ants is read from the first line.
+func parseAntAttribute(graph *Graph, line string) error {
+	fields := strings.Fields(strings.TrimPrefix(line, "##"))
+	if len(fields) != 2 {
+		return fmt.Errorf("invalid ant attribute %q, expected ##slow ANTS or ##priority ANTS", line)
+	}
+	bounds := strings.SplitN(fields[1], "-", 2)
+	from, err := strconv.Atoi(bounds[0])
+	to := from
+	if err == nil && len(bounds) == 2 {
+		to, err = strconv.Atoi(bounds[1])
+	}
+	if err != nil || from < 1 || to < from {
+		return fmt.Errorf("invalid ants in %q, expected a number or a range like 1-5", line)
+	}
+	if to > graph.Ants {
+		return fmt.Errorf("there's no ant %d, the map has %d ants", to, graph.Ants)
+	}
+
+	ants := &graph.Slow
+	if fields[0] == AttributePriority {
+		ants = &graph.Priority
+	}
+	if *ants == nil {
+		*ants = make(map[int]bool)
+	}
+	for ant := from; ant <= to; ant++ {
+		(*ants)[ant] = true
+	}
+	return nil
+}
+
+// Speed returns the number of turns the ant takes to go through a tunnel.
+func (graph *Graph) Speed(ant int) int {
+	if graph.Slow[ant] {
+		return 2
+	}
+	return 1
+}
+
+// HasAntAttributes reports whether some ants are slow or have priority.
+func (graph *Graph) HasAntAttributes() bool {
+	return len(graph.Slow) > 0 || len(graph.Priority) > 0
+}
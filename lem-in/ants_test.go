@@ -0,0 +1,73 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAntAttribute(t *testing.T) {
+	tests := []struct {
+		line     string
+		slow     map[int]bool
+		priority map[int]bool
+		err      string
+	}{
+		{"##slow 2", map[int]bool{2: true}, nil, ""},
+		{"##priority 1-3", nil, map[int]bool{1: true, 2: true, 3: true}, ""},
+		{"##slow", nil, nil, `invalid ant attribute "##slow", expected ##slow ANTS or ##priority ANTS`},
+		{"##slow 3-1", nil, nil, `invalid ants in "##slow 3-1", expected a number or a range like 1-5`},
+		{"##priority 0", nil, nil, `invalid ants in "##priority 0", expected a number or a range like 1-5`},
+		{"##slow 4-5", nil, nil, "there's no ant 5, the map has 4 ants"},
+	}
+	for _, test := range tests {
+		graph := &Graph{Ants: 4}
+		err := parseAntAttribute(graph, test.line)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: error %v, want %s", test.line, err, test.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(graph.Slow, test.slow) || !reflect.DeepEqual(graph.Priority, test.priority) {
+			t.Errorf("%s: slow %v and priority %v (%v), want %v and %v", test.line, graph.Slow, graph.Priority, err, test.slow, test.priority)
+		}
+	}
+}
+
+func TestPlanAntAttributes(t *testing.T) {
+	const chain = "3\n##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\nb-e\n"
+	const twoPaths = "4\n##start\ns 0 0\na 1 0\nb 1 1\nc 2 1\n##end\ne 2 0\ns-a\na-e\ns-b\nb-c\nc-e\n"
+	tests := []struct {
+		name     string
+		content  string
+		arrivals []int // Turn each ant reaches the end room in, by number - 1
+	}{
+		{"no attributes", twoPaths, []int{2, 3, 3, 4}},
+		{"comment that reads like an attribute", twoPaths + "#slow 1\n", []int{2, 3, 3, 4}},
+		{"slow ant", chain + "##slow 1\n", []int{6, 7, 8}},
+		{"slow ant goes first", chain + "##slow 2\n", []int{7, 6, 8}},
+		{"priority ants", twoPaths + "##priority 3-4\n", []int{3, 4, 2, 3}},
+		{"priority before slow", "2\n##start\ns 0 0\na 1 0\n##end\ne 2 0\ns-a\na-e\n##slow 1\n##priority 2\n", []int{5, 2}},
+	}
+	for _, test := range tests {
+		graph, _, err := ParseMap(test.content)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		turns, stranded := Plan(graph, Solve(graph))
+		if err := ValidateTurns(graph, turns, Stops(graph, false)); err != nil || stranded > 0 {
+			t.Errorf("%s: %d stranded ants, %v", test.name, stranded, err)
+		}
+		arrivals := make([]int, graph.Ants)
+		for i, turn := range turns {
+			for _, move := range turn {
+				if move.Room == graph.End {
+					arrivals[move.Ant-1] = i + 1
+				}
+			}
+		}
+		if !reflect.DeepEqual(arrivals, test.arrivals) {
+			t.Errorf("%s: arrivals %v, want %v", test.name, arrivals, test.arrivals)
+		}
+	}
+}
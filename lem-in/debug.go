@@ -0,0 +1,73 @@
+package lemin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SolverStep is one iteration of Suurballe's algorithm: the augmenting path found,
+// the rooms it split and unsplit, and the path set it leads to.
+type SolverStep struct {
+	Iteration int `json:"iteration"`
+	*Augmentation
+	Paths      [][]string `json:"paths"`
+	TotalSteps int        `json:"total_steps"`
+	Chosen     bool       `json:"chosen"` // The path set ComputePaths keeps
+}
+
+// TraceSolver runs the solver on a reduced copy of the graph, as Solve does, and
+// returns every iteration, with the path set BestPaths picks marked.
+func TraceSolver(graph *Graph) []SolverStep {
+	clone := CloneGraph(graph)
+	ReduceGraph(clone)
+
+	var steps []SolverStep
+	var candidates []*Paths
+	eachCandidate(clone, clone.Ants, func(paths *Paths, augmentation *Augmentation) {
+		step := SolverStep{Iteration: len(steps) + 1, Augmentation: augmentation, TotalSteps: paths.TotalSteps}
+		for _, path := range paths.AllPaths {
+			step.Paths = append(step.Paths, listRooms(path))
+		}
+		steps = append(steps, step)
+		candidates = append(candidates, paths)
+	})
+	best, _ := BestPaths(candidates, clone.Ants)
+	for i, paths := range candidates {
+		steps[i].Chosen = paths == best
+	}
+	return steps
+}
+
+// PrintSolverTrace prints the iterations as text, or as a JSON array when asJSON is set.
+func PrintSolverTrace(steps []SolverStep, asJSON bool) {
+	if asJSON {
+		out, _ := json.MarshalIndent(steps, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	for _, step := range steps {
+		fmt.Printf("Iteration %d\n", step.Iteration)
+		fmt.Printf("  Augmenting path: %s\n", strings.Join(step.Path, " -> "))
+		fmt.Printf("  Split: %s\n", roomList(step.Split))
+		fmt.Printf("  Unsplit: %s\n", roomList(step.Unsplit))
+		fmt.Printf("  Paths (%d, %d total steps):\n", len(step.Paths), step.TotalSteps)
+		for i, path := range step.Paths {
+			fmt.Printf("    %d: %s\n", i+1, strings.Join(path, " -> "))
+		}
+	}
+	for _, step := range steps {
+		if step.Chosen {
+			fmt.Printf("Chosen: iteration %d, %d paths, %d total steps\n", step.Iteration, len(step.Paths), step.TotalSteps)
+		}
+	}
+}
+
+// roomList joins the rooms with spaces, or returns "none".
+func roomList(rooms []string) string {
+	if len(rooms) == 0 {
+		return "none"
+	}
+	return strings.Join(rooms, " ")
+}
@@ -0,0 +1,90 @@
+package lemin
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// trap is a map whose shortest path blocks the two paths that don't share a room, so
+// the second iteration cancels the tunnels a-m-d of the first.
+const trap = `2
+##start
+s 0 0
+a 1 0
+m 2 0
+d 3 0
+b 1 1
+x 2 1
+z 3 1
+c 1 2
+y 2 2
+w 3 2
+##end
+e 4 0
+s-a
+a-m
+m-d
+d-e
+a-b
+b-x
+x-z
+z-e
+s-c
+c-y
+y-w
+w-d
+`
+
+func TestTraceSolver(t *testing.T) {
+	graph, _, err := ParseMap(trap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := TraceSolver(graph)
+	want := []SolverStep{
+		{Iteration: 1, Augmentation: &Augmentation{
+			Path:    []string{"s", "a", "m", "d", "e"},
+			Split:   []string{"a", "m", "d"},
+			Unsplit: []string{},
+		}, Paths: [][]string{{"s", "a", "m", "d", "e"}}, TotalSteps: 6, Chosen: true},
+		{Iteration: 2, Augmentation: &Augmentation{
+			Path:    []string{"s", "c", "y", "w", "d", "m", "a", "b", "x", "z", "e"},
+			Split:   []string{"c", "y", "w", "d", "b", "x", "z"},
+			Unsplit: []string{"m"},
+		}, Paths: [][]string{{"s", "a", "b", "x", "z", "e"}, {"s", "c", "y", "w", "d", "e"}}, TotalSteps: 6},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("%d iterations, want %d", len(steps), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(*steps[i].Augmentation, *want[i].Augmentation) {
+			t.Errorf("iteration %d: %+v, want %+v", i+1, *steps[i].Augmentation, *want[i].Augmentation)
+		}
+		// Paths of the same length come in any order.
+		sort.Slice(steps[i].Paths, func(j, k int) bool {
+			return strings.Join(steps[i].Paths[j], " ") < strings.Join(steps[i].Paths[k], " ")
+		})
+		steps[i].Augmentation = want[i].Augmentation
+		if !reflect.DeepEqual(steps[i], want[i]) {
+			t.Errorf("iteration %d: %+v, want %+v", i+1, steps[i], want[i])
+		}
+	}
+}
+
+func TestTraceSolverSplitsCorridors(t *testing.T) {
+	graph, _, err := ReadFile("../lemin_test/audit/example00.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := TraceSolver(graph)
+	if len(steps) == 0 {
+		t.Fatal("no iterations")
+	}
+	// Every room of the path but the start and end rooms is contracted into a corridor.
+	path := steps[0].Path
+	if !reflect.DeepEqual(steps[0].Split, path[1:len(path)-1]) {
+		t.Errorf("split %v, want %v", steps[0].Split, path[1:len(path)-1])
+	}
+}
@@ -0,0 +1,75 @@
+package lemin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kinds of events
+const (
+	EventClose = "close" // A tunnel closes
+	EventFail  = "fail"  // A room can no longer be entered
+)
+
+// Event is a change to the map happening at the start of a turn, written in the
+// input as `##close a-b at 5` or `##fail room7 at 10`.
+type Event struct {
+	Kind string
+	A, B string // Rooms of the closed tunnel, or the failed room in A
+	Turn int
+}
+
+// isEvent reports whether a line is an event directive.
+func isEvent(line string) bool {
+	return strings.HasPrefix(line, "##"+EventClose+" ") || strings.HasPrefix(line, "##"+EventFail+" ")
+}
+
+// ParseEvent parses an event line.
+func ParseEvent(line string) (Event, error) {
+	fields := strings.Fields(strings.TrimPrefix(line, "##"))
+	if len(fields) != 4 || fields[2] != "at" {
+		return Event{}, fmt.Errorf("invalid event %q, expected ##close a-b at TURN or ##fail room at TURN", line)
+	}
+	turn, err := strconv.Atoi(fields[3])
+	if err != nil || turn < 1 {
+		return Event{}, fmt.Errorf("invalid turn in event %q", line)
+	}
+	event := Event{Kind: fields[0], A: fields[1], Turn: turn}
+	if event.Kind == EventClose {
+		rooms := strings.Split(fields[1], "-")
+		if len(rooms) != 2 {
+			return Event{}, fmt.Errorf("invalid tunnel in event %q", line)
+		}
+		event.A, event.B = rooms[0], rooms[1]
+	}
+	return event, nil
+}
+
+// checkEvents makes sure the events refer to tunnels and rooms of the graph.
+func checkEvents(graph *Graph) error {
+	for _, event := range graph.Events {
+		switch event.Kind {
+		case EventClose:
+			if node := graph.Rooms[event.A]; node == nil || node.Edges[event.B] == 0 {
+				return fmt.Errorf("can't close %s-%s, there's no such tunnel", event.A, event.B)
+			}
+		case EventFail:
+			// A room without tunnels is declared but not in Rooms, failing it changes nothing.
+			if _, declared := graph.Coords[event.A]; !declared && graph.Rooms[event.A] == nil {
+				return fmt.Errorf("can't fail %s, there's no such room", event.A)
+			}
+			if event.A == graph.Start || event.A == graph.End {
+				return fmt.Errorf("can't fail the start or end room")
+			}
+		}
+	}
+	return nil
+}
+
+func (event Event) String() string {
+	if event.Kind == EventClose {
+		return fmt.Sprintf("##close %s-%s at %d", event.A, event.B, event.Turn)
+	}
+	return fmt.Sprintf("##fail %s at %d", event.A, event.Turn)
+}
@@ -0,0 +1,58 @@
+package lemin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Candidate is a path set ComputePaths evaluated, with the steps it needs for the ants.
+type Candidate struct {
+	Lengths []int // Moves along each path, shortest first
+	Steps   int
+	Chosen  bool
+}
+
+// ExplainPaths returns every path set ComputePaths evaluates for the graph, in the
+// order the Suurballe iterations found them, with the one it keeps marked.
+func ExplainPaths(graph *Graph) []Candidate {
+	clone := CloneGraph(graph)
+	ReduceGraph(clone)
+	sets := CandidatePaths(clone, clone.Ants)
+	best, _ := BestPaths(sets, clone.Ants)
+
+	candidates := make([]Candidate, len(sets))
+	for i, paths := range sets {
+		for _, path := range paths.AllPaths {
+			candidates[i].Lengths = append(candidates[i].Lengths, path.Len()-1)
+		}
+		candidates[i].Steps = paths.calculateSteps(clone.Ants)
+		candidates[i].Chosen = paths == best
+	}
+	return candidates
+}
+
+// PrintExplanation prints a line for each candidate path set and which one won.
+func PrintExplanation(candidates []Candidate, ants int) {
+	var rows [][]string
+	for i, candidate := range candidates {
+		lengths := make([]string, len(candidate.Lengths))
+		for j, length := range candidate.Lengths {
+			lengths[j] = strconv.Itoa(length)
+		}
+		chosen := ""
+		if candidate.Chosen {
+			chosen = "<- chosen"
+		}
+		rows = append(rows, []string{strconv.Itoa(i + 1), strconv.Itoa(len(candidate.Lengths)),
+			strings.Join(lengths, ","), strconv.Itoa(candidate.Steps), strconv.Itoa(candidate.Steps - 1), chosen})
+	}
+	printTable([]string{"set", "paths", "lengths", "steps", "turns", ""}, rows, false)
+
+	for i, candidate := range candidates {
+		if candidate.Chosen {
+			fmt.Printf("\nSet %d wins: %d paths move %d ants in %d turns, the fewest of all sets; ties go to the set found first.\n",
+				i+1, len(candidate.Lengths), ants, candidate.Steps-1)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplainPaths(t *testing.T) {
+	graph, _, err := ParseMap(trap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both sets move the two ants in five turns, the set found first is kept.
+	want := []Candidate{
+		{Lengths: []int{4}, Steps: 6, Chosen: true},
+		{Lengths: []int{5, 5}, Steps: 6},
+	}
+	if candidates := ExplainPaths(graph); !reflect.DeepEqual(candidates, want) {
+		t.Errorf("%+v, want %+v", candidates, want)
+	}
+
+	graph.Ants = 4
+	want = []Candidate{
+		{Lengths: []int{4}, Steps: 8},
+		{Lengths: []int{5, 5}, Steps: 7, Chosen: true},
+	}
+	if candidates := ExplainPaths(graph); !reflect.DeepEqual(candidates, want) {
+		t.Errorf("4 ants: %+v, want %+v", candidates, want)
+	}
+}
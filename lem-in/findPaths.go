@@ -9,35 +9,58 @@ import (
 
 // ComputePaths computes all possible paths using Suurballe's algorithm.
 func ComputePaths(graph *Graph) *Paths {
+	bestPaths, _ := BestPaths(CandidatePaths(graph, graph.Ants), graph.Ants)
+	return bestPaths
+}
+
+// CandidatePaths returns the path sets found by successive Suurballe iterations,
+// stopping after limit sets or when no more paths can be found.
+func CandidatePaths(graph *Graph, limit int) []*Paths {
+	var candidates []*Paths
+	eachCandidate(graph, limit, func(paths *Paths, _ *Augmentation) {
+		candidates = append(candidates, paths)
+	})
+	return candidates
+}
 
-	var bestPaths, newPaths *Paths
-	if bestPaths = GetNextPaths(graph); bestPaths == nil {
-		return nil
+// eachCandidate runs the Suurballe iterations CandidatePaths does and calls visit with
+// the path set each finds and the augmentation leading to it.
+func eachCandidate(graph *Graph, limit int, visit func(paths *Paths, augmentation *Augmentation)) {
+	for found := 0; found < limit; found++ {
+		paths, augmentation := augment(graph)
+		if paths == nil {
+			return
+		}
+		visit(paths, augmentation)
 	}
+}
 
-	minPathFound := 1
-	for minPathFound < graph.Ants {
-		if newPaths = GetNextPaths(graph); newPaths == nil {
+// BestPaths returns the candidate needing the fewest steps for antCount ants, along with
+// those steps. Only the first antCount candidates are considered since more paths than
+// ants can't all be used.
+func BestPaths(candidates []*Paths, antCount int) (*Paths, int) {
+	var bestPaths *Paths
+	var bestSteps int
+	for i, newPaths := range candidates {
+		if i == antCount {
 			break
 		}
-
-		if newPaths.TotalSteps < bestPaths.TotalSteps {
-			bestPaths = newPaths
+		if steps := newPaths.calculateSteps(antCount); bestPaths == nil || steps < bestSteps {
+			bestPaths, bestSteps = newPaths, steps
 		}
-		minPathFound++
 	}
-
-	return bestPaths
+	return bestPaths, bestSteps
 }
 
-// GetNextPaths finds the next set of paths.
-func GetNextPaths(graph *Graph) *Paths {
+// augment finds the next set of paths along with the changes the augmenting path made
+// to the graph, or nil when there's no more path.
+func augment(graph *Graph) (*Paths, *Augmentation) {
 	if !Dijkstra(graph) {
-		return nil
+		return nil, nil
 	}
 	SetPrices(graph)
-	CachePath(graph)
-	return PathsFromGraph(graph)
+	augmentation := CachePath(graph)
+	return PathsFromGraph(graph), augmentation
 }
 
 // Dijkstra's algorithm to find the shortest path.
@@ -72,19 +95,20 @@ func ResetGraph(graph *Graph) {
 func RelaxEdge(graph *Graph, pq *PriorityQueue, current, next string) {
 	currentNode := graph.Rooms[current]
 	nextNode := graph.Rooms[next]
+	length := currentNode.Edges[next]
 
 	if current == graph.End || next == graph.Start || nextNode.Prev == current {
 		return
 	}
 
-	if currentNode.Prev == next && currentNode.CostIn+currentNode.PriceIn < nextNode.CostOut+nextNode.PriceOut+1 {
+	if currentNode.Prev == next && currentNode.CostIn+currentNode.PriceIn < nextNode.CostOut+nextNode.PriceOut+length {
 		nextNode.EdgeOut = current
-		nextNode.CostOut = currentNode.CostIn - 1 + currentNode.PriceIn - nextNode.PriceOut
+		nextNode.CostOut = currentNode.CostIn - length + currentNode.PriceIn - nextNode.PriceOut
 		heap.Push(pq, &PQNode{Cost: nextNode.CostOut, Room: next})
 		RelaxHiddenEdge(graph, pq, next)
-	} else if currentNode.Prev != next && currentNode.CostOut+currentNode.PriceOut+1 < nextNode.CostIn+nextNode.PriceIn {
+	} else if currentNode.Prev != next && currentNode.CostOut+currentNode.PriceOut+length < nextNode.CostIn+nextNode.PriceIn {
 		nextNode.EdgeIn = current
-		nextNode.CostIn = currentNode.CostOut + 1 + currentNode.PriceOut - nextNode.PriceIn
+		nextNode.CostIn = currentNode.CostOut + length + currentNode.PriceOut - nextNode.PriceIn
 		heap.Push(pq, &PQNode{Cost: nextNode.CostIn, Room: next})
 		RelaxHiddenEdge(graph, pq, next)
 	}
@@ -144,34 +168,64 @@ func (paths *Paths) pathLength(i int) int {
 
 // calculateSteps calculates the total steps required for all ants to reach the end.
 func (paths *Paths) calculateSteps(antCount int) int {
-	l := len(paths.AllPaths) - 1
+	used, sum := paths.usedPaths(antCount)
 	shortest := paths.pathLength(0)
-	longest := paths.pathLength(l)
-	var sum int
-	for i := 0; i < paths.NumPaths; i++ {
-		sum += longest - paths.pathLength(i)
-	}
-	antsPerPath := longest - shortest + (antCount-sum)/paths.NumPaths
-	if (antCount-sum)%paths.NumPaths > 0 {
+	longest := paths.pathLength(used - 1)
+	antsPerPath := longest - shortest + (antCount-sum)/used
+	if (antCount-sum)%used > 0 {
 		antsPerPath++
 	}
 	return shortest + antsPerPath - 1
 }
 
-// UnrollPath reconstructs a path from the end node to the start node.
+// usedPaths returns how many of the paths, shortest first, the ants are sent along, and
+// the ants it takes to fill the shorter of them up to the longest. A path is left out
+// when there aren't enough ants for that, as it would only make them arrive later.
+func (paths *Paths) usedPaths(antCount int) (int, int) {
+	for used := paths.NumPaths; ; used-- {
+		longest := paths.pathLength(used - 1)
+		sum := 0
+		for i := 0; i < used; i++ {
+			sum += longest - paths.pathLength(i)
+		}
+		if used == 1 || antCount >= sum {
+			return used, sum
+		}
+	}
+}
+
+// Turns returns the number of turns printed for the paths. TotalSteps counts the
+// start room as a step, so it is one more than the number of turns.
+func (paths *Paths) Turns() int {
+	return paths.TotalSteps - 1
+}
+
+// UnrollPath reconstructs a path from the end node to the start node, expanding contracted corridors.
 func UnrollPath(graph *Graph, v string) *list.List {
 	path := list.New()
 	path.PushFront(graph.End)
+	pushCorridor(graph, path, v, graph.End)
 	for v != graph.Start {
 		path.PushFront(v)
+		pushCorridor(graph, path, graph.Rooms[v].Prev, v)
 		v = graph.Rooms[v].Prev
 	}
 	path.PushFront(graph.Start)
 	return path
 }
 
-// CachePath caches the path found by Dijkstra's algorithm.
-func CachePath(graph *Graph) {
+// Augmentation is what CachePath changed in the graph for the path Dijkstra's algorithm found.
+type Augmentation struct {
+	Path    []string `json:"path"`    // Rooms of the augmenting path from the start room
+	Split   []string `json:"split"`   // Rooms the path now goes through
+	Unsplit []string `json:"unsplit"` // Rooms of an earlier path it cancels
+}
+
+// CachePath caches the path found by Dijkstra's algorithm and returns the changes made.
+// The split and unsplit rooms include the rooms of the contracted corridors on the way.
+func CachePath(graph *Graph) *Augmentation {
+	augmentation := &Augmentation{Path: []string{graph.End}}
+	split, unsplitRooms := list.New(), list.New()
 	var unsplit bool
 	w := graph.End
 	v := graph.Rooms[w].EdgeIn
@@ -180,16 +234,31 @@ func CachePath(graph *Graph) {
 		if graph.Rooms[v].Prev == w {
 			if unsplit {
 				UnsplitNode(graph, w)
+				unsplitRooms.PushFront(w)
 			}
+			pushCorridor(graph, unsplitRooms, v, w)
 			unsplit = true
 			w, v = v, graph.Rooms[v].EdgeIn
 		} else {
 			graph.Rooms[w].Prev = v
 			SplitNode(graph, w)
+			if graph.Rooms[w].Split {
+				split.PushFront(w)
+			}
+			pushCorridor(graph, split, v, w)
 			unsplit = false
 			w, v = v, graph.Rooms[v].EdgeOut
 		}
+		augmentation.Path = append(augmentation.Path, w)
+	}
+	augmentation.Split, augmentation.Unsplit = listRooms(split), listRooms(unsplitRooms)
+	// The path was walked back from the end room.
+	rooms := augmentation.Path
+	for i, j := 0, len(rooms)-1; i < j; i, j = i+1, j-1 {
+		rooms[i], rooms[j] = rooms[j], rooms[i]
 	}
+	augmentation.Path = expandCorridors(graph, augmentation.Path)
+	return augmentation
 }
 
 // UnsplitNode resets a split node.
@@ -0,0 +1,75 @@
+package lemin
+
+import (
+	"container/list"
+	"sort"
+)
+
+// CloneGraph returns a copy of the graph's rooms and tunnels with fresh solver state,
+// so the copy can be reduced and solved without touching the original.
+func CloneGraph(graph *Graph) *Graph {
+	clone := &Graph{
+		Rooms:  make(map[string]*Node, len(graph.Rooms)),
+		Exits:  list.New(),
+		Start:  graph.Start,
+		End:    graph.End,
+		Ants:   graph.Ants,
+		Coords: graph.Coords,
+	}
+	for name, node := range graph.Rooms {
+		edges := make(map[string]int, len(node.Edges))
+		for neighbor, length := range node.Edges {
+			edges[neighbor] = length
+		}
+		clone.Rooms[name] = &Node{Edges: edges, Prev: "L"}
+	}
+	if graph.Corridors != nil {
+		clone.Corridors = make(map[[2]string][]string, len(graph.Corridors))
+		for key, corridor := range graph.Corridors {
+			clone.Corridors[key] = corridor
+		}
+	}
+	return clone
+}
+
+// AddTunnel links two rooms with a tunnel of length one, adding a room that had no tunnel yet.
+func AddTunnel(graph *Graph, a, b string) {
+	for _, room := range []string{a, b} {
+		if graph.Rooms[room] == nil {
+			graph.Rooms[room] = &Node{Edges: make(map[string]int), Prev: "L"}
+		}
+	}
+	graph.Rooms[a].Edges[b] = 1
+	graph.Rooms[b].Edges[a] = 1
+}
+
+// RemoveTunnel deletes the tunnel between two rooms.
+func RemoveTunnel(graph *Graph, a, b string) {
+	delete(graph.Rooms[a].Edges, b)
+	delete(graph.Rooms[b].Edges, a)
+}
+
+// DuplicateRoom adds a copy of a room linked to the same neighbors and returns its name.
+func DuplicateRoom(graph *Graph, name string) string {
+	copyName := name + "'"
+	for graph.Rooms[copyName] != nil {
+		copyName += "'"
+	}
+	node := &Node{Edges: make(map[string]int), Prev: "L"}
+	for neighbor, length := range graph.Rooms[name].Edges {
+		node.Edges[neighbor] = length
+		graph.Rooms[neighbor].Edges[copyName] = length
+	}
+	graph.Rooms[copyName] = node
+	return copyName
+}
+
+// sortedRooms returns the room names in order.
+func sortedRooms(graph *Graph) []string {
+	names := make([]string, 0, len(graph.Rooms))
+	for name := range graph.Rooms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package lemin
+
+import (
+	"math"
+	"sort"
+)
+
+// Point is a position on a drawing surface.
+type Point struct {
+	X, Y float64
+}
+
+// Layout returns a position for every room inside a width x height area, keeping margin
+// free on each side. Declared coordinates are scaled to fit the area; when a room has
+// none or two rooms share a position, the rooms are laid out in layers by their
+// distance from the start room instead.
+func Layout(graph *Graph, width, height, margin float64) map[string]Point {
+	if usableCoords(graph) {
+		return scaledLayout(graph, width, height, margin)
+	}
+	return layeredLayout(graph, width, height, margin)
+}
+
+// usableCoords reports whether every room has its own declared position.
+func usableCoords(graph *Graph) bool {
+	taken := make(map[Coord]bool)
+	for name := range graph.Rooms {
+		coord, ok := graph.Coords[name]
+		if !ok || taken[coord] {
+			return false
+		}
+		taken[coord] = true
+	}
+	return true
+}
+
+// scaledLayout scales the declared coordinates uniformly and centers them in the area.
+func scaledLayout(graph *Graph, width, height, margin float64) map[string]Point {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for name := range graph.Rooms {
+		c := graph.Coords[name]
+		minX, maxX = math.Min(minX, float64(c.X)), math.Max(maxX, float64(c.X))
+		minY, maxY = math.Min(minY, float64(c.Y)), math.Max(maxY, float64(c.Y))
+	}
+
+	innerW, innerH := width-2*margin, height-2*margin
+	scale := math.Inf(1)
+	if maxX > minX {
+		scale = innerW / (maxX - minX)
+	}
+	if maxY > minY {
+		scale = math.Min(scale, innerH/(maxY-minY))
+	}
+	if math.IsInf(scale, 1) {
+		scale = 1
+	}
+	offsetX := margin + (innerW-(maxX-minX)*scale)/2
+	offsetY := margin + (innerH-(maxY-minY)*scale)/2
+
+	positions := make(map[string]Point, len(graph.Rooms))
+	for name := range graph.Rooms {
+		c := graph.Coords[name]
+		positions[name] = Point{
+			X: offsetX + (float64(c.X)-minX)*scale,
+			Y: offsetY + (float64(c.Y)-minY)*scale,
+		}
+	}
+	return positions
+}
+
+// layeredLayout places rooms in columns by their distance from the start room, with the
+// start room in the first column and the end room alone in the last one.
+func layeredLayout(graph *Graph, width, height, margin float64) map[string]Point {
+	layer := map[string]int{graph.Start: 0}
+	queue := []string{graph.Start}
+	last := 0
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		for next := range graph.Rooms[room].Edges {
+			if _, seen := layer[next]; !seen && next != graph.End {
+				layer[next] = layer[room] + 1
+				if layer[next] > last {
+					last = layer[next]
+				}
+				queue = append(queue, next)
+			}
+		}
+	}
+	// Rooms the start can't reach get a column of their own before the end room.
+	unreached := false
+	for name := range graph.Rooms {
+		if _, seen := layer[name]; !seen && name != graph.End {
+			layer[name] = last + 1
+			unreached = true
+		}
+	}
+	if unreached {
+		last++
+	}
+	layer[graph.End] = last + 1
+	last++
+
+	columns := make([][]string, last+1)
+	for name, l := range layer {
+		if graph.Rooms[name] != nil {
+			columns[l] = append(columns[l], name)
+		}
+	}
+
+	positions := make(map[string]Point, len(graph.Rooms))
+	for l, column := range columns {
+		sort.Strings(column)
+		x := margin + float64(l)*(width-2*margin)/float64(last)
+		for i, name := range column {
+			y := margin + float64(i+1)*(height-2*margin)/float64(len(column)+1)
+			positions[name] = Point{X: x, Y: y}
+		}
+	}
+	return positions
+}
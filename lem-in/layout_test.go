@@ -0,0 +1,54 @@
+package lemin
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLayoutScalesDeclaredCoordinates(t *testing.T) {
+	graph, _, err := ParseMap("1\n##start\ns 0 0\na 5 0\n##end\ne 10 10\ns-a\na-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10x10 units scaled by 8 to fill the 80 pixels of height, centered in the 180 of width.
+	want := map[string]Point{"s": {60, 10}, "a": {100, 10}, "e": {140, 90}}
+	positions := Layout(graph, 200, 100, 10)
+	for name, point := range want {
+		if positions[name] != point {
+			t.Errorf("%s at %v, want %v", name, positions[name], point)
+		}
+	}
+}
+
+func TestLayoutFallsBackToLayers(t *testing.T) {
+	// a and s share a position, so the rooms go in columns by distance from s.
+	graph, _, err := ParseMap("1\n##start\ns 0 0\na 0 0\nb 1 0\n##end\ne 2 0\ns-a\ns-b\na-e\nb-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions := Layout(graph, 200, 100, 10)
+	want := map[string]Point{"s": {10, 50}, "a": {100, 10 + 80.0/3}, "b": {100, 10 + 160.0/3}, "e": {190, 50}}
+	for name, point := range want {
+		if math.Abs(positions[name].X-point.X) > 1e-9 || math.Abs(positions[name].Y-point.Y) > 1e-9 {
+			t.Errorf("%s at %v, want %v", name, positions[name], point)
+		}
+	}
+}
+
+func TestLayoutStaysInside(t *testing.T) {
+	for _, file := range []string{"example00.txt", "example01.txt", "example02.txt", "example03.txt", "example04.txt", "example05.txt"} {
+		graph, _, err := ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		positions := Layout(graph, 800, 600, 40)
+		if len(positions) != len(graph.Rooms) {
+			t.Errorf("%s: %d positions for %d rooms", file, len(positions), len(graph.Rooms))
+		}
+		for name, point := range positions {
+			if point.X < 40 || point.X > 760 || point.Y < 40 || point.Y > 560 {
+				t.Errorf("%s: %s at %v, outside the area", file, name, point)
+			}
+		}
+	}
+}
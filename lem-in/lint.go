@@ -0,0 +1,240 @@
+package lemin
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Severity levels reported by the linter.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// LintIssue is a single finding reported by the linter.
+type LintIssue struct {
+	Line     int    `json:"line"` // 0 when the issue concerns the whole file
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// RunLint implements the `lint` command.
+func RunLint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*format != "text" && *format != "json") {
+		fmt.Println("Usage: program lint [--format=text|json] input_file")
+		os.Exit(1)
+	}
+
+	fileBytes, err := ReadInput(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	issues := LintMap(string(fileBytes))
+
+	if *format == "json" {
+		out, _ := json.MarshalIndent(issues, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		for _, issue := range issues {
+			if issue.Line == 0 {
+				fmt.Printf("%s: %s: %s\n", flags.Arg(0), issue.Severity, issue.Message)
+			} else {
+				fmt.Printf("%s:%d: %s: %s\n", flags.Arg(0), issue.Line, issue.Severity, issue.Message)
+			}
+		}
+	}
+
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			os.Exit(1)
+		}
+	}
+}
+
+// LintMap checks a map for questionable input that ReadFile accepts silently.
+// Fatal parse errors are reported with the error severity.
+func LintMap(content string) []LintIssue {
+	issues := make([]LintIssue, 0)
+	if _, _, err := ParseMap(content); err != nil {
+		issues = append(issues, LintIssue{Severity: SeverityError, Message: err.Error()})
+	}
+
+	roomLine := make(map[string]int)   // line where each room first appears
+	tunnelLine := make(map[string]int) // line where each tunnel is declared
+	edges := make(map[string]map[string]bool)
+	var start, end string
+	link := func(from, to string) {
+		if edges[from] == nil {
+			edges[from] = make(map[string]bool)
+		}
+		edges[from][to] = true
+	}
+
+	lines := strings.Split(strings.TrimRight(content, " \t\r\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		lineNum := i + 1
+		if i == 0 || line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "##") {
+			switch directive := strings.TrimPrefix(line, "##"); {
+			case directive == "start":
+				start = nextRoomName(lines, i)
+			case directive == "end":
+				end = nextRoomName(lines, i)
+			case directive == "pickup", isEvent(line), isAntAttribute(line):
+			case editDistance(directive, "start") <= 2:
+				issues = append(issues, LintIssue{lineNum, SeverityWarning, fmt.Sprintf("%q looks like a misspelled ##start directive", line)})
+			case editDistance(directive, "end") <= 2:
+				issues = append(issues, LintIssue{lineNum, SeverityWarning, fmt.Sprintf("%q looks like a misspelled ##end directive", line)})
+			default:
+				issues = append(issues, LintIssue{lineNum, SeverityInfo, fmt.Sprintf("unknown directive %q is ignored", line)})
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if fields := strings.Fields(line); len(fields) == 3 {
+			if _, seen := roomLine[fields[0]]; !seen {
+				roomLine[fields[0]] = lineNum
+			}
+			continue
+		}
+		parts := strings.Split(line, "-")
+		if len(parts) != 2 {
+			continue
+		}
+		from, to := parts[0], parts[1]
+		if from == to {
+			issues = append(issues, LintIssue{lineNum, SeverityWarning, fmt.Sprintf("tunnel %s links room %s to itself and is ignored", line, from)})
+			continue
+		}
+		key := tunnelKey(from, to)
+		if first, dup := tunnelLine[key]; dup {
+			issues = append(issues, LintIssue{lineNum, SeverityWarning, fmt.Sprintf("duplicate tunnel %s (first declared on line %d)", line, first)})
+			continue
+		}
+		tunnelLine[key] = lineNum
+		for _, room := range parts {
+			if _, seen := roomLine[room]; !seen {
+				roomLine[room] = lineNum
+			}
+		}
+		link(from, to)
+		link(to, from)
+	}
+
+	if start != "" && end != "" {
+		issues = append(issues, lintReachability(roomLine, edges, start, end)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// lintReachability reports rooms that cannot reach the end room and rooms on dead-end branches.
+func lintReachability(roomLine map[string]int, edges map[string]map[string]bool, start, end string) []LintIssue {
+	var issues []LintIssue
+
+	reachesEnd := map[string]bool{end: true}
+	queue := []string{end}
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		for next := range edges[room] {
+			if !reachesEnd[next] {
+				reachesEnd[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// Peel rooms with a single tunnel until only rooms that can sit between two others remain.
+	degree := make(map[string]int)
+	for room, next := range edges {
+		degree[room] = len(next)
+	}
+	deadEnd := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for room := range edges {
+			if room == start || room == end || deadEnd[room] || degree[room] > 1 {
+				continue
+			}
+			deadEnd[room] = true
+			changed = true
+			for next := range edges[room] {
+				degree[next]--
+			}
+		}
+	}
+
+	for room, line := range roomLine {
+		switch {
+		case !reachesEnd[room]:
+			issues = append(issues, LintIssue{line, SeverityWarning, fmt.Sprintf("room %s cannot reach the end room %s", room, end)})
+		case deadEnd[room]:
+			issues = append(issues, LintIssue{line, SeverityInfo, fmt.Sprintf("room %s is on a dead-end branch", room)})
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// nextRoomName returns the name of the room declared on the line after line i.
+func nextRoomName(lines []string, i int) string {
+	if i+1 < len(lines) {
+		if fields := strings.Fields(lines[i+1]); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// tunnelKey returns the same key for both directions of a tunnel.
+func tunnelKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "-" + b
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,71 @@
+package lemin
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLintAuditExamples(t *testing.T) {
+	for _, file := range []string{"example00.txt", "example01.txt", "example02.txt", "example03.txt", "example04.txt", "example05.txt"} {
+		content, err := os.ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issues := LintMap(string(content)); len(issues) > 0 {
+			t.Errorf("%s: %v, want no issues", file, issues)
+		}
+	}
+	for _, file := range []string{"badexample00.txt", "badexample01.txt"} {
+		content, err := os.ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if issues := LintMap(string(content)); len(issues) == 0 || issues[0].Severity != SeverityError {
+			t.Errorf("%s: %v, want an error first", file, issues)
+		}
+	}
+}
+
+func TestLintMap(t *testing.T) {
+	// The map used by each case, before the lines the case adds.
+	const header = "2\n##start\ns 0 0\n##end\ne 1 0\n"
+	tests := []struct {
+		content string
+		issues  []LintIssue
+	}{
+		{header + "s-e\n", []LintIssue{}},
+		{"2\n##strat\ns 0 0\n##end\ne 1 0\ns-e\n", []LintIssue{
+			{0, SeverityError, "wrong start/end room"},
+			{2, SeverityWarning, `"##strat" looks like a misspelled ##start directive`},
+		}},
+		{"2\n##start\ns 0 0\n##edn\ne 1 0\ns-e\n", []LintIssue{
+			{0, SeverityError, "wrong start/end room"},
+			{4, SeverityWarning, `"##edn" looks like a misspelled ##end directive`},
+		}},
+		{header + "##colour red\ns-e\n", []LintIssue{
+			{6, SeverityInfo, `unknown directive "##colour red" is ignored`},
+		}},
+		{header + "##pickup\np 2 0\ns-e\ns-p\np-e\n", []LintIssue{}},
+		{header + "p 2 0\ns-e\ns-p\np-e\n##close s-p at 3\n##fail p at 4\n", []LintIssue{}},
+		{header + "s-e\n##slow 1\n##priority 2\n", []LintIssue{}},
+		{header + "s-e\ns-s\n", []LintIssue{
+			{7, SeverityWarning, "tunnel s-s links room s to itself and is ignored"},
+		}},
+		{header + "s-e\ne-s\n", []LintIssue{
+			{7, SeverityWarning, "duplicate tunnel e-s (first declared on line 6)"},
+		}},
+		{header + "a 2 0\nb 3 0\ns-e\na-b\n", []LintIssue{
+			{6, SeverityWarning, "room a cannot reach the end room e"},
+			{7, SeverityWarning, "room b cannot reach the end room e"},
+		}},
+		{header + "a 2 0\ns-e\ns-a\n", []LintIssue{
+			{6, SeverityInfo, "room a is on a dead-end branch"},
+		}},
+	}
+	for _, test := range tests {
+		if issues := LintMap(test.content); !reflect.DeepEqual(issues, test.issues) {
+			t.Errorf("%q:\ngot  %v\nwant %v", test.content, issues, test.issues)
+		}
+	}
+}
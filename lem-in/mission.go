@@ -0,0 +1,90 @@
+package lemin
+
+import "fmt"
+
+// Stops returns the rooms every ant goes through in order: the start room, the pickup
+// room if the map has one, the end room, and the start room again on a round trip.
+func Stops(graph *Graph, roundTrip bool) []string {
+	stops := []string{graph.Start}
+	if graph.Pickup != "" {
+		stops = append(stops, graph.Pickup)
+	}
+	stops = append(stops, graph.End)
+	if roundTrip {
+		stops = append(stops, graph.Start)
+	}
+	return stops
+}
+
+// Planned reports whether the ants have to be planned rather than scheduled along the
+// paths: to go through a pickup room or back to the start room, around events, or with
+// slow ants or ants with priority.
+func Planned(graph *Graph, stops []string) bool {
+	return len(stops) > 2 || len(graph.Events) > 0 || graph.HasAntAttributes()
+}
+
+// PlanTurns returns the moves of each turn taking the ants through the stops, planned
+// when the map needs it and scheduled along the paths otherwise, and the number of ants
+// that can't reach the last stop.
+func PlanTurns(graph *Graph, paths *Paths, stops []string) ([][]Move, int, error) {
+	if len(stops) > 2 {
+		turns, err := PlanMission(graph, stops)
+		return turns, 0, err
+	}
+	if Planned(graph, stops) {
+		turns, stranded := Plan(graph, paths)
+		return turns, stranded, nil
+	}
+	return Schedule(paths, graph.Ants), 0, nil
+}
+
+// PlanMission returns the moves of each turn taking the ants through the stops. Every
+// leg between two stops is solved on its own, without the stops the ants haven't been
+// through yet, and each ant leaves a stop along the path of the next leg that gets it
+// to the next stop quickest, around the routes already planned, so legs never put two
+// ants in a room. The start and end rooms hold any number of ants and the pickup room
+// one at a time: an ant waits in the room before it until it is free.
+func PlanMission(graph *Graph, stops []string) ([][]Move, error) {
+	if len(graph.Events) > 0 {
+		return nil, fmt.Errorf("events can't be combined with a pickup room or a round trip")
+	}
+	p := newPlanner(graph, stops)
+	var legs []*Paths // Legs joined by stops holding one ant
+	for i := 1; i < len(stops); i++ {
+		leg := CloneGraph(graph)
+		for _, room := range laterStops(stops, i) {
+			removeRoom(leg, room)
+		}
+		leg.Start, leg.End = stops[i-1], stops[i]
+		paths := Solve(leg)
+		if paths == nil {
+			return nil, fmt.Errorf("no path from %s to %s", leg.Start, leg.End)
+		}
+		legs = append(legs, paths)
+		if stops[i] == graph.Start || stops[i] == graph.End {
+			p.dispatch(p.all(), 1, legs...)
+			legs = nil
+		}
+	}
+	turns, stranded := p.turns()
+	if stranded > 0 {
+		return nil, fmt.Errorf("%d ants can't reach %s", stranded, stops[len(stops)-1])
+	}
+	return turns, nil
+}
+
+// laterStops returns the stops after the i-th the ants can't go through before it,
+// leaving out those they have already been through.
+func laterStops(stops []string, i int) []string {
+	var later []string
+	for _, room := range stops[i+1:] {
+		visited := false
+		for _, earlier := range stops[:i+1] {
+			visited = visited || earlier == room
+		}
+		if !visited {
+			later = append(later, room)
+		}
+	}
+	return later
+}
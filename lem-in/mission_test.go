@@ -0,0 +1,41 @@
+package lemin
+
+import "testing"
+
+func TestPlanMission(t *testing.T) {
+	const pickup = "3\n##start\ns 0 0\na 1 0\n##pickup\np 1 1\n##end\ne 2 0\ns-a\na-e\ns-p\np-e\n"
+	tests := []struct {
+		name      string
+		content   string
+		roundTrip bool
+		turns     int
+		err       string
+	}{
+		{"pickup one ant at a time", pickup, false, 4, ""},
+		{"pickup behind the end room", "3\n##start\ns 0 0\na 1 0\nb 2 0\n##pickup\np 3 0\n##end\ne 2 1\ns-e\ne-p\ns-a\na-b\nb-p\n", false, 6, ""},
+		{"round trip", "3\n##start\ns 0 0\na 1 0\nb 1 1\n##end\ne 2 0\ns-a\na-e\ns-b\nb-e\n", true, 5, ""},
+		{"round trip through the pickup", "2\n##start\ns 0 0\n##pickup\np 1 1\n##end\ne 2 0\ns-p\np-e\n", true, 6, ""},
+		{"pickup out of reach", "2\n##start\ns 0 0\na 1 0\n##pickup\np 1 1\nb 1 2\n##end\ne 2 0\ns-a\na-e\nb-p\n", false, 0, "no path from s to p"},
+		{"pickup with events", pickup + "##close a-e at 3\n", false, 0, "events can't be combined with a pickup room or a round trip"},
+	}
+	for _, test := range tests {
+		graph, _, err := ParseMap(test.content)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		stops := Stops(graph, test.roundTrip)
+		turns, err := PlanMission(graph, stops)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: error %v, want %s", test.name, err, test.err)
+			}
+			continue
+		}
+		if err == nil {
+			err = ValidateTurns(graph, turns, stops)
+		}
+		if err != nil || len(turns) != test.turns {
+			t.Errorf("%s: %d turns (%v), want %d", test.name, len(turns), err, test.turns)
+		}
+	}
+}
@@ -1,22 +1,22 @@
 package lemin
 
 import (
+	"bufio"
 	"container/list"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 )
 
 // distributeAnts assigns ants to paths to minimize total steps.
 func (paths *Paths) distributeAnts(antCount int) {
 	paths.Assignment = make([]int, paths.NumPaths)
-	l := len(paths.AllPaths) - 1
-	longest := paths.pathLength(l)
-	var sum int
-	for i := 0; i < paths.NumPaths; i++ {
-		sum += longest - paths.pathLength(i)
-	}
-	avgAnts := float32(antCount-sum) / float32(paths.NumPaths)
-	rem := (avgAnts - float32(int(avgAnts))) * float32(paths.NumPaths)
-	for i := 0; i < paths.NumPaths; i++ {
+	used, sum := paths.usedPaths(antCount)
+	longest := paths.pathLength(used - 1)
+	avgAnts := float32(antCount-sum) / float32(used)
+	rem := (avgAnts - float32(int(avgAnts))) * float32(used)
+	for i := 0; i < used; i++ {
 		paths.Assignment[i] = longest - paths.pathLength(i) + int(avgAnts)
 		if rem > 0 {
 			paths.Assignment[i]++
@@ -25,42 +25,114 @@ func (paths *Paths) distributeAnts(antCount int) {
 	}
 }
 
+// Move is an ant entering a room during a turn.
+type Move struct {
+	Ant  int    `json:"ant"`
+	Room string `json:"room"`
+}
+
 // SimulateAnts simulates the movement of ants along the paths and prints the steps.
-func SimulateAnts(paths *Paths, antCount int) {
+// When stream is set each turn is flushed as soon as it is computed, so a reader on a
+// pipe sees the turns as they come instead of in large blocks.
+func SimulateAnts(paths *Paths, antCount int, stream bool) {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	ScheduleAnts(paths, antCount, func(turn []Move) {
+		printTurn(out, turn, stream)
+	})
+}
+
+// PrintTurns prints the moves of each turn on a line, flushing every turn when stream is set.
+func PrintTurns(turns [][]Move, stream bool) {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	for _, turn := range turns {
+		printTurn(out, turn, stream)
+	}
+}
+
+func printTurn(out *bufio.Writer, turn []Move, stream bool) {
+	for _, move := range turn {
+		fmt.Fprintf(out, "L%d-%v ", move.Ant, move.Room)
+	}
+	fmt.Fprintln(out)
+	if stream {
+		out.Flush()
+	}
+}
+
+// Schedule returns the moves made in each turn.
+func Schedule(paths *Paths, antCount int) [][]Move {
+	var turns [][]Move
+	ScheduleAnts(paths, antCount, func(turn []Move) {
+		turns = append(turns, turn)
+	})
+	return turns
+}
+
+// ScheduleAnts distributes the ants along the paths and calls emit with the moves of
+// each turn, in order. Ants already on their way move first, then new ants leave the
+// start room, one per path with ants left to send.
+func ScheduleAnts(paths *Paths, antCount int, emit func(turn []Move)) {
 	paths.distributeAnts(antCount) // Distribute ants into each path
-	var lastAnt int
-	antNum, activeAnt := 1, 1
-	antPositions := make(map[int]*list.Element)
-	for j := 0; j < paths.TotalSteps; j++ {
-		if len(antPositions) > 0 {
-			fmt.Println()
-		}
-		for k := activeAnt; k <= lastAnt; k++ {
-			if pos, ok := antPositions[k]; ok && pos != nil {
-				fmt.Printf("L%d-%v ", k, pos.Value)
-				antPositions[k] = pos.Next()
+	remaining := make([]int, paths.NumPaths)
+	copy(remaining, paths.Assignment)
+
+	antNum := 1
+	var moving []int                            // Ants on their way, in the order they left
+	antPositions := make(map[int]*list.Element) // Next room of each moving ant
+	for {
+		var turn []Move
+		stillMoving := moving[:0]
+		for _, ant := range moving {
+			pos := antPositions[ant]
+			turn = append(turn, Move{ant, pos.Value.(string)})
+			if pos.Next() != nil {
+				antPositions[ant] = pos.Next()
+				stillMoving = append(stillMoving, ant)
 			} else {
-				activeAnt = k + 1
-				delete(antPositions, k)
+				delete(antPositions, ant)
 			}
 		}
-		for i := 0; i < paths.NumPaths; i++ {
-			if antNum > antCount {
-				break
-			}
-			if paths.Assignment[i] <= 0 {
+		moving = stillMoving
+
+		for i := 0; i < paths.NumPaths && antNum <= antCount; i++ {
+			if remaining[i] <= 0 {
 				continue
-			} else {
-				paths.Assignment[i]--
 			}
+			remaining[i]--
 			nextRoom := paths.AllPaths[i].Front().Next()
-			if nextRoom != nil {
-				fmt.Printf("L%d-%v ", antNum, nextRoom.Value)
+			turn = append(turn, Move{antNum, nextRoom.Value.(string)})
+			if nextRoom.Next() != nil {
 				antPositions[antNum] = nextRoom.Next()
+				moving = append(moving, antNum)
 			}
 			antNum++
 		}
 
-		lastAnt = antNum - 1
+		if len(turn) == 0 {
+			return
+		}
+		emit(turn)
+	}
+}
+
+// ParseMoves parses the moves printed by SimulateAnts, one turn per line. An empty
+// line is a turn without moves, as when only a slow ant is on its way.
+func ParseMoves(text string) ([][]Move, error) {
+	var turns [][]Move
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		tokens := strings.Fields(line)
+		turn := make([]Move, 0, len(tokens))
+		for _, token := range tokens {
+			parts := strings.SplitN(token, "-", 2)
+			ant, err := strconv.Atoi(strings.TrimPrefix(parts[0], "L"))
+			if len(parts) != 2 || !strings.HasPrefix(parts[0], "L") || err != nil {
+				return nil, fmt.Errorf("invalid move %q", token)
+			}
+			turn = append(turn, Move{ant, parts[1]})
+		}
+		turns = append(turns, turn)
 	}
+	return turns, nil
 }
@@ -0,0 +1,105 @@
+package lemin
+
+import (
+	"container/list"
+	"fmt"
+	"testing"
+)
+
+func TestScheduleMovesEveryAnt(t *testing.T) {
+	tests := []struct {
+		file  string
+		turns int
+	}{
+		{"example00.txt", 6},
+		{"example01.txt", 8},
+		{"example02.txt", 11},
+		{"example03.txt", 6},
+		{"example04.txt", 6},
+		{"example05.txt", 8}, // L3 used to be dropped before reaching the end room
+	}
+	for _, test := range tests {
+		graph, _, err := ReadFile("../lemin_test/audit/" + test.file)
+		if err != nil {
+			t.Fatalf("%s: %v", test.file, err)
+		}
+		paths := Solve(graph)
+		turns := Schedule(paths, graph.Ants)
+		if len(turns) != test.turns || paths.Turns() != test.turns {
+			t.Errorf("%s: %d turns, Turns() says %d, want %d", test.file, len(turns), paths.Turns(), test.turns)
+		}
+
+		arrived := make(map[int]int)
+		for _, turn := range turns {
+			for _, move := range turn {
+				if move.Room == graph.End {
+					arrived[move.Ant]++
+				}
+			}
+		}
+		for ant := 1; ant <= graph.Ants; ant++ {
+			if arrived[ant] != 1 {
+				t.Errorf("%s: L%d reaches %s %d times", test.file, ant, graph.End, arrived[ant])
+			}
+		}
+	}
+}
+
+func TestParseMoves(t *testing.T) {
+	turns, err := ParseMoves("L1-a L2-b \nL1-e L2-room-2\n\nL3-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]Move{{{1, "a"}, {2, "b"}}, {{1, "e"}, {2, "room-2"}}, {}, {{3, "e"}}}
+	if fmt.Sprint(turns) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", turns, want)
+	}
+
+	for _, text := range []string{"1-a", "L1a", "Lx-a"} {
+		if _, err := ParseMoves(text); err == nil {
+			t.Errorf("%q parsed without an error", text)
+		}
+	}
+}
+
+func TestDistributeAntsLeavesOutLongPaths(t *testing.T) {
+	tests := []struct {
+		lengths    []int // Rooms of each path, shortest first
+		ants       int
+		assignment []int
+		turns      int
+	}{
+		{[]int{3, 10}, 1, []int{1, 0}, 2},
+		{[]int{3, 10}, 3, []int{3, 0}, 4},
+		{[]int{3, 10}, 7, []int{7, 0}, 8},
+		{[]int{3, 10}, 9, []int{8, 1}, 9},
+		{[]int{3, 4, 10}, 2, []int{2, 0, 0}, 3},
+		{[]int{3, 4, 10}, 4, []int{3, 1, 0}, 4},
+		{[]int{3, 3}, 1, []int{1, 0}, 2},
+		{[]int{3, 3}, 5, []int{3, 2}, 4},
+	}
+	for _, test := range tests {
+		paths := new(Paths)
+		for i, length := range test.lengths {
+			path := list.New()
+			path.PushBack("start")
+			for j := 2; j < length; j++ {
+				path.PushBack(fmt.Sprintf("r%d_%d", i, j))
+			}
+			path.PushBack("end")
+			paths.AllPaths = append(paths.AllPaths, path)
+		}
+		paths.NumPaths = len(paths.AllPaths)
+
+		paths.distributeAnts(test.ants)
+		if fmt.Sprint(paths.Assignment) != fmt.Sprint(test.assignment) {
+			t.Errorf("%v with %d ants: assignment %v, want %v", test.lengths, test.ants, paths.Assignment, test.assignment)
+		}
+		if steps := paths.calculateSteps(test.ants); steps-1 != test.turns {
+			t.Errorf("%v with %d ants: %d turns, want %d", test.lengths, test.ants, steps-1, test.turns)
+		}
+		if turns := len(Schedule(paths, test.ants)); turns != test.turns {
+			t.Errorf("%v with %d ants: scheduled in %d turns, want %d", test.lengths, test.ants, turns, test.turns)
+		}
+	}
+}
@@ -9,43 +9,39 @@ import (
 	"strings"
 )
 
-func GetGraph() *Graph {
-	args := os.Args[1:]
+// GetGraph reads the graph from the file given in args, along with the file content.
+func GetGraph(args []string) (*Graph, string) {
 	if len(args) != 1 {
-		fmt.Println("Usage: program input_file")
+		fmt.Println("Usage: program [--tui] [--stream] [--trace-ants] [--stats [--stats-format=table|csv]] [--debug-solver [--debug-format=text|json]] [--explain] [--round-trip] input_file")
 		os.Exit(1)
 	}
 
-	graph, _, err := ReadFile(args[0])
+	graph, content, err := ReadFile(args[0])
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
 
-	return graph
+	return graph, content
 }
 
 // Reads the input file and constructs the adjacency list, start/end rooms, and number of ants.
 func ReadFile(filePath string) (*Graph, string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		file, err = os.Open("./lemin_test/audit/" + filePath)
-		if err != nil {
-			return nil, "", fmt.Errorf("can't open your input file")
-		}
-	}
-	defer file.Close()
-
-	fileBytes, err := io.ReadAll(file)
+	fileBytes, err := ReadInput(filePath)
 	if err != nil {
-		return nil, "", fmt.Errorf("can't read your input file")
+		return nil, "", err
 	}
+	return ParseMap(string(fileBytes))
+}
 
-	lines := strings.Split(strings.TrimSpace(string(fileBytes)), "\n")
+// ParseMap constructs the graph from the content of a map and returns it along with the trimmed content.
+func ParseMap(content string) (*Graph, string, error) {
+	var err error
+	lines := strings.Split(strings.TrimSpace(content), "\n")
 
-	graph := &Graph{Rooms: make(map[string]*Node)}
+	graph := &Graph{Rooms: make(map[string]*Node), Coords: make(map[string]Coord)}
 	graph.Exits = list.New()
-	var startFound, endFound bool
+	var startFound, endFound, pickupFound bool
 
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
@@ -79,12 +75,45 @@ func ReadFile(filePath string) (*Graph, string, error) {
 			endFound =true
 			continue
 		}
+		if line == "##pickup" {
+			if pickupFound {
+				return nil, "", fmt.Errorf("can't have more than one pickup")
+			}
+			graph.Pickup, err = ParseStartEnd("Pickup", i, lines)
+			if err != nil {
+				return nil, "", err
+			}
+			pickupFound = true
+			continue
+		}
 		if  strings.HasPrefix(line, "L") {
 			return nil, "", fmt.Errorf("can't start a room name with L")
 		}
+		if isEvent(line) {
+			event, err := ParseEvent(line)
+			if err != nil {
+				return nil, "", err
+			}
+			graph.Events = append(graph.Events, event)
+			continue
+		}
+		if isAntAttribute(line) {
+			if err := parseAntAttribute(graph, line); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		if fields := strings.Fields(line); len(fields) == 3 {
+			x, errX := strconv.Atoi(fields[1])
+			y, errY := strconv.Atoi(fields[2])
+			if errX == nil && errY == nil {
+				graph.Coords[fields[0]] = Coord{X: x, Y: y}
+			}
+			continue
+		}
 
 		parts := strings.Split(line, "-")
 		if len(parts) == 2 {
@@ -95,13 +124,13 @@ func ReadFile(filePath string) (*Graph, string, error) {
 			}
 			// Add nodes and edges to the graph
 			if graph.Rooms[from] == nil {
-				graph.Rooms[from] = &Node{Edges: make(map[string]bool), Prev: "L"}
+				graph.Rooms[from] = &Node{Edges: make(map[string]int), Prev: "L"}
 			}
 			if graph.Rooms[to] == nil {
-				graph.Rooms[to] = &Node{Edges: make(map[string]bool), Prev: "L"}
+				graph.Rooms[to] = &Node{Edges: make(map[string]int), Prev: "L"}
 			}
-			graph.Rooms[from].Edges[to] = true
-			graph.Rooms[to].Edges[from] = true
+			graph.Rooms[from].Edges[to] = 1
+			graph.Rooms[to].Edges[from] = 1
 		}
 	}
 
@@ -119,8 +148,37 @@ func ReadFile(filePath string) (*Graph, string, error) {
 	if _, endExist := graph.Rooms[graph.End]; !endExist {
 		return nil, "", fmt.Errorf("end room isn't linked")
 	}
+	if pickupFound {
+		if graph.Pickup == graph.Start || graph.Pickup == graph.End {
+			return nil, "", fmt.Errorf("wrong pickup room")
+		}
+		if _, pickupExist := graph.Rooms[graph.Pickup]; !pickupExist {
+			return nil, "", fmt.Errorf("pickup room isn't linked")
+		}
+	}
+	if err := checkEvents(graph); err != nil {
+		return nil, "", err
+	}
+
+	return graph, strings.TrimSpace(content), nil
+}
+
+// ReadInput returns the raw content of an input file, falling back to the audit examples directory.
+func ReadInput(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		file, err = os.Open("./lemin_test/audit/" + filePath)
+		if err != nil {
+			return nil, fmt.Errorf("can't open your input file")
+		}
+	}
+	defer file.Close()
 
-	return graph, strings.TrimSpace(string(fileBytes)), nil
+	fileBytes, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("can't read your input file")
+	}
+	return fileBytes, nil
 }
 
 // Parses the start or end room from the input.
@@ -0,0 +1,214 @@
+package lemin
+
+import "container/list"
+
+// ReduceGraph shrinks the graph before solving. Rooms that cannot lie on any simple
+// start->end path are dropped, and corridors of rooms with exactly two tunnels are
+// contracted into a single weighted tunnel. UnrollPath expands them back.
+func ReduceGraph(graph *Graph) {
+	PruneGraph(graph)
+	ContractCorridors(graph)
+}
+
+// PruneGraph removes the rooms that are not part of a biconnected block lying between
+// the start and end rooms in the block-cut tree. Those are exactly the rooms that no
+// simple start->end path can visit.
+func PruneGraph(graph *Graph) {
+	blocks := biconnectedBlocks(graph)
+
+	// Walk the block-cut tree from start to end. Vertices are keyed by room name and
+	// blocks by their index, so both kinds of tree node can share the queue.
+	type treeNode struct {
+		room  string
+		block int
+	}
+	memberOf := make(map[string][]int)
+	for i, block := range blocks {
+		for _, room := range block {
+			memberOf[room] = append(memberOf[room], i)
+		}
+	}
+	parent := map[treeNode]treeNode{}
+	root := treeNode{room: graph.Start, block: -1}
+	parent[root] = root
+	queue := []treeNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		var next []treeNode
+		if node.block < 0 {
+			for _, b := range memberOf[node.room] {
+				next = append(next, treeNode{block: b})
+			}
+		} else {
+			for _, room := range blocks[node.block] {
+				next = append(next, treeNode{room: room, block: -1})
+			}
+		}
+		for _, n := range next {
+			if _, seen := parent[n]; !seen {
+				parent[n] = node
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	target := treeNode{room: graph.End, block: -1}
+	if _, reached := parent[target]; !reached {
+		return // Nothing to keep; the solver reports that no path exists.
+	}
+	keep := make(map[string]bool)
+	for node := target; node != root; node = parent[node] {
+		if node.block >= 0 {
+			for _, room := range blocks[node.block] {
+				keep[room] = true
+			}
+		}
+	}
+
+	for name := range graph.Rooms {
+		if !keep[name] {
+			removeRoom(graph, name)
+		}
+	}
+}
+
+// biconnectedBlocks returns the rooms of each biconnected block reachable from the start room.
+func biconnectedBlocks(graph *Graph) [][]string {
+	var blocks [][]string
+	var stack [][2]string
+	disc := make(map[string]int)
+	low := make(map[string]int)
+	time := 0
+
+	var visit func(u, from string)
+	visit = func(u, from string) {
+		time++
+		disc[u], low[u] = time, time
+		for w := range graph.Rooms[u].Edges {
+			if disc[w] == 0 {
+				stack = append(stack, [2]string{u, w})
+				visit(w, u)
+				if low[w] < low[u] {
+					low[u] = low[w]
+				}
+				if low[w] >= disc[u] {
+					// u separates w's subtree: pop its block off the edge stack.
+					members := make(map[string]bool)
+					for {
+						edge := stack[len(stack)-1]
+						stack = stack[:len(stack)-1]
+						members[edge[0]], members[edge[1]] = true, true
+						if edge[0] == u && edge[1] == w {
+							break
+						}
+					}
+					block := make([]string, 0, len(members))
+					for room := range members {
+						block = append(block, room)
+					}
+					blocks = append(blocks, block)
+				}
+			} else if w != from && disc[w] < disc[u] {
+				stack = append(stack, [2]string{u, w})
+				if disc[w] < low[u] {
+					low[u] = disc[w]
+				}
+			}
+		}
+	}
+	visit(graph.Start, "")
+	return blocks
+}
+
+// ContractCorridors replaces every maximal chain of rooms with exactly two tunnels by a
+// single tunnel whose length is the length of the chain. Chains whose ends are already
+// linked are left alone since the graph can't hold parallel tunnels.
+func ContractCorridors(graph *Graph) {
+	inner := func(name string) bool {
+		node := graph.Rooms[name]
+		return node != nil && len(node.Edges) == 2 && name != graph.Start && name != graph.End
+	}
+
+	for name := range graph.Rooms {
+		if !inner(name) {
+			continue
+		}
+		// Walk both ways from this room to the ends of its corridor.
+		var sides [2][]string
+		var ends [2]string
+		var lengths [2]int
+		i := 0
+		for next := range graph.Rooms[name].Edges {
+			prev, curr := name, next
+			lengths[i] = graph.Rooms[name].Edges[next]
+			for inner(curr) && curr != name {
+				sides[i] = append(sides[i], curr)
+				for n, w := range graph.Rooms[curr].Edges {
+					if n != prev {
+						prev, curr = curr, n
+						lengths[i] += w
+						break
+					}
+				}
+			}
+			ends[i] = curr
+			i++
+		}
+		a, b := ends[0], ends[1]
+		if a == b || a == name || graph.Rooms[a].Edges[b] > 0 {
+			continue
+		}
+
+		// Corridor rooms in order from a to b.
+		corridor := make([]string, 0, len(sides[0])+len(sides[1])+1)
+		for j := len(sides[0]) - 1; j >= 0; j-- {
+			corridor = append(corridor, sides[0][j])
+		}
+		corridor = append(corridor, name)
+		corridor = append(corridor, sides[1]...)
+
+		for _, room := range corridor {
+			removeRoom(graph, room)
+		}
+		graph.Rooms[a].Edges[b] = lengths[0] + lengths[1]
+		graph.Rooms[b].Edges[a] = lengths[0] + lengths[1]
+		reversed := make([]string, len(corridor))
+		for j, room := range corridor {
+			reversed[len(corridor)-1-j] = room
+		}
+		if graph.Corridors == nil {
+			graph.Corridors = make(map[[2]string][]string)
+		}
+		graph.Corridors[[2]string{a, b}] = corridor
+		graph.Corridors[[2]string{b, a}] = reversed
+	}
+}
+
+// removeRoom deletes a room and all of its tunnels from the graph.
+func removeRoom(graph *Graph, name string) {
+	for neighbor := range graph.Rooms[name].Edges {
+		if node := graph.Rooms[neighbor]; node != nil {
+			delete(node.Edges, name)
+		}
+	}
+	delete(graph.Rooms, name)
+}
+
+// pushCorridor pushes the rooms of the contracted corridor from->to, if any, to the front of the path.
+func pushCorridor(graph *Graph, path *list.List, from, to string) {
+	corridor := graph.Corridors[[2]string{from, to}]
+	for i := len(corridor) - 1; i >= 0; i-- {
+		path.PushFront(corridor[i])
+	}
+}
+
+// expandCorridors returns the rooms with the rooms of the contracted corridors between them.
+func expandCorridors(graph *Graph, rooms []string) []string {
+	expanded := []string{rooms[0]}
+	for i := 1; i < len(rooms); i++ {
+		expanded = append(expanded, graph.Corridors[[2]string{rooms[i-1], rooms[i]}]...)
+		expanded = append(expanded, rooms[i])
+	}
+	return expanded
+}
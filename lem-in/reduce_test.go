@@ -0,0 +1,72 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReduceGraphKeepsTheTurns(t *testing.T) {
+	for _, file := range []string{"example00.txt", "example01.txt", "example02.txt", "example03.txt", "example04.txt", "example05.txt"} {
+		graph, _, err := ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := ComputePaths(graph).TotalSteps
+
+		graph, _, _ = ReadFile("../lemin_test/audit/" + file)
+		original, _, _ := ReadFile("../lemin_test/audit/" + file)
+		ReduceGraph(graph)
+		paths := ComputePaths(graph)
+		if paths.TotalSteps != want {
+			t.Errorf("%s: %d steps once reduced, want %d", file, paths.TotalSteps, want)
+		}
+		// The corridors are expanded back, so the paths only use tunnels of the map.
+		for _, path := range paths.AllPaths {
+			for e := path.Front(); e.Next() != nil; e = e.Next() {
+				from, to := e.Value.(string), e.Next().Value.(string)
+				if original.Rooms[from].Edges[to] == 0 {
+					t.Errorf("%s: path %s goes from %s to %s without a tunnel", file, PathToString(path), from, to)
+				}
+			}
+		}
+	}
+}
+
+func TestContractCorridors(t *testing.T) {
+	graph, _, err := ParseMap("1\n##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\nb-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ContractCorridors(graph)
+	if len(graph.Rooms) != 2 || graph.Rooms["s"].Edges["e"] != 3 {
+		t.Errorf("rooms %v, want s and e linked by a tunnel of length 3", sortedRooms(graph))
+	}
+	if corridor := graph.Corridors[[2]string{"s", "e"}]; !reflect.DeepEqual(corridor, []string{"a", "b"}) {
+		t.Errorf("corridor from s to e %v, want [a b]", corridor)
+	}
+	if corridor := graph.Corridors[[2]string{"e", "s"}]; !reflect.DeepEqual(corridor, []string{"b", "a"}) {
+		t.Errorf("corridor from e to s %v, want [b a]", corridor)
+	}
+
+	// s and e are already linked, and the graph can't hold a second tunnel between them.
+	graph, _, _ = ParseMap("1\n##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\nb-e\ns-e\n")
+	ContractCorridors(graph)
+	if len(graph.Rooms) != 4 || graph.Corridors != nil {
+		t.Errorf("rooms %v and corridors %v, want the graph unchanged", sortedRooms(graph), graph.Corridors)
+	}
+}
+
+func TestPruneGraph(t *testing.T) {
+	// x is a dead end and y-z a loop hanging off a, so no simple path visits them.
+	graph, _, err := ParseMap("1\n##start\ns 0 0\na 1 0\nx 0 1\ny 1 1\nz 2 1\n##end\ne 2 0\ns-a\na-e\ns-x\na-y\ny-z\nz-a\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	PruneGraph(graph)
+	if rooms := sortedRooms(graph); !reflect.DeepEqual(rooms, []string{"a", "e", "s"}) {
+		t.Errorf("rooms %v, want [a e s]", rooms)
+	}
+	if len(graph.Rooms["a"].Edges) != 2 {
+		t.Errorf("a has tunnels to %v, want s and e", graph.Rooms["a"].Edges)
+	}
+}
@@ -0,0 +1,285 @@
+package lemin
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// Colors of the rendered frames, by palette index.
+var renderPalette = color.Palette{
+	color.RGBA{255, 255, 255, 255}, // background
+	color.RGBA{0, 0, 0, 255},       // tunnels and room outlines
+	color.RGBA{255, 255, 224, 255}, // rooms
+	color.RGBA{0, 160, 0, 255},     // start room
+	color.RGBA{0, 0, 200, 255},     // end room
+	color.RGBA{255, 0, 0, 255},     // ants
+}
+
+const (
+	colorBackground uint8 = iota
+	colorLine
+	colorRoom
+	colorStart
+	colorEnd
+	colorAnt
+)
+
+// RunRender implements the `render` command.
+func RunRender(args []string) {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	gifPath := flags.String("gif", "", "write an animated GIF to this file")
+	framesDir := flags.String("frames", "", "write one PNG per turn to this directory")
+	width := flags.Int("width", 800, "frame width in pixels")
+	height := flags.Int("height", 600, "frame height in pixels")
+	delay := flags.Int("delay", 50, "GIF delay between turns, in hundredths of a second")
+	roundTrip := flags.Bool("round-trip", false, "send the ants back to the start room from the end room")
+	flags.Parse(args)
+	if flags.NArg() != 1 || (*gifPath == "" && *framesDir == "") || *width < 100 || *height < 100 {
+		fmt.Println("Usage: program render [--gif out.gif] [--frames dir] [--width=W] [--height=H] [--delay=D] [--round-trip] input_file")
+		os.Exit(1)
+	}
+
+	graph, _, err := ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	paths := Solve(graph)
+	if paths == nil {
+		fmt.Println("No paths found")
+		os.Exit(1)
+	}
+	turns, _, err := PlanTurns(graph, paths, Stops(graph, *roundTrip))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if *framesDir != "" {
+		if err := os.MkdirAll(*framesDir, 0o755); err != nil {
+			fmt.Printf("can't create frames directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var animation *gifWriter
+	if *gifPath != "" {
+		if animation, err = newGIFWriter(*gifPath, *delay); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// Each frame is written as it is drawn, so long solutions don't have to fit in memory.
+	t := 0
+	err = RenderFrames(graph, turns, *width, *height, func(frame *image.Paletted) error {
+		if *framesDir != "" {
+			if err := writeFrame(*framesDir, t, frame); err != nil {
+				return err
+			}
+		}
+		t++
+		if animation != nil {
+			return animation.Add(frame)
+		}
+		return nil
+	})
+	if err == nil && animation != nil {
+		err = animation.Close()
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+// RenderFrames draws the graph once before the first turn and once after every turn,
+// with a dot for each ant between the start and end rooms, and passes each frame to
+// emit as soon as it is drawn. It stops at the first error emit returns.
+func RenderFrames(graph *Graph, turns [][]Move, width, height int, emit func(frame *image.Paletted) error) error {
+	radius := roomRadius(len(graph.Rooms), width, height)
+	positions := Layout(graph, float64(width), float64(height), float64(radius+4))
+
+	// The graph is the same in every frame, so draw it once and copy it.
+	background := image.NewPaletted(image.Rect(0, 0, width, height), renderPalette)
+	names := sortedRooms(graph)
+	for _, name := range names {
+		for neighbor := range graph.Rooms[name].Edges {
+			if name < neighbor {
+				drawLine(background, positions[name], positions[neighbor], colorLine)
+			}
+		}
+	}
+	for _, name := range names {
+		fill := colorRoom
+		switch name {
+		case graph.Start:
+			fill = colorStart
+		case graph.End:
+			fill = colorEnd
+		}
+		fillCircle(background, positions[name], radius, colorLine)
+		fillCircle(background, positions[name], radius-1, fill)
+	}
+
+	antRooms := make(map[int]string)
+	for t := 0; t <= len(turns); t++ {
+		if t > 0 {
+			for _, move := range turns[t-1] {
+				antRooms[move.Ant] = move.Room
+			}
+		}
+		frame := image.NewPaletted(background.Rect, renderPalette)
+		copy(frame.Pix, background.Pix)
+		for _, room := range antRooms {
+			if room != graph.Start && room != graph.End {
+				fillCircle(frame, positions[room], (radius+1)/2, colorAnt)
+			}
+		}
+		if err := emit(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roomRadius picks a room size that keeps rooms apart on a frame of the given size.
+func roomRadius(rooms, width, height int) int {
+	radius := int(math.Sqrt(float64(width*height)/float64(rooms+1)) / 4)
+	if radius > 12 {
+		return 12
+	}
+	if radius < 3 {
+		return 3
+	}
+	return radius
+}
+
+// writeFrame writes the frame of the turn as a PNG file in the directory.
+func writeFrame(dir string, turn int, frame *image.Paletted) error {
+	file, err := os.Create(filepath.Join(dir, fmt.Sprintf("turn_%05d.png", turn)))
+	if err != nil {
+		return fmt.Errorf("can't create frame file: %v", err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, frame); err != nil {
+		return fmt.Errorf("can't write frame: %v", err)
+	}
+	return nil
+}
+
+// Bytes image/gif writes before the first frame: the signature and the logical screen
+// descriptor, without a global color table as every frame carries its palette.
+const gifHeaderSize = 13
+
+// gifWriter writes an animated GIF a frame at a time. image/gif only encodes whole
+// animations, so each frame is encoded on its own and its blocks are copied after the
+// header. The last frame added is held back, as the final one is shown longer.
+type gifWriter struct {
+	file    *os.File
+	out     *bufio.Writer
+	delay   int
+	last    *image.Paletted
+	started bool
+}
+
+func newGIFWriter(path string, delay int) (*gifWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't create GIF file: %v", err)
+	}
+	return &gifWriter{file: file, out: bufio.NewWriter(file), delay: delay}, nil
+}
+
+// Add writes the frame added before and holds back this one.
+func (w *gifWriter) Add(frame *image.Paletted) error {
+	if w.last != nil {
+		if err := w.write(w.last, w.delay); err != nil {
+			return err
+		}
+	}
+	w.last = frame
+	return nil
+}
+
+// Close writes the final frame, holding it a little longer before looping, and ends the file.
+func (w *gifWriter) Close() error {
+	defer w.file.Close()
+	if w.last != nil {
+		if err := w.write(w.last, 4*w.delay); err != nil {
+			return err
+		}
+	}
+	w.out.WriteByte(0x3b) // Trailer
+	if err := w.out.Flush(); err != nil {
+		return fmt.Errorf("can't write GIF: %v", err)
+	}
+	return w.file.Close()
+}
+
+func (w *gifWriter) write(frame *image.Paletted, delay int) error {
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, &gif.GIF{Image: []*image.Paletted{frame}, Delay: []int{delay}}); err != nil {
+		return fmt.Errorf("can't write GIF: %v", err)
+	}
+	data := buf.Bytes()
+	if !w.started {
+		w.out.Write(data[:gifHeaderSize])
+		w.out.WriteString("\x21\xff\x0bNETSCAPE2.0\x03\x01\x00\x00\x00") // Loop forever
+		w.started = true
+	}
+	// Without the trailer ending the single frame file
+	if _, err := w.out.Write(data[gifHeaderSize : len(data)-1]); err != nil {
+		return fmt.Errorf("can't write GIF: %v", err)
+	}
+	return nil
+}
+
+// fillCircle fills a disc centered on p.
+func fillCircle(img *image.Paletted, p Point, radius int, index uint8) {
+	cx, cy := int(math.Round(p.X)), int(math.Round(p.Y))
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx*dx+dy*dy <= radius*radius {
+				img.SetColorIndex(cx+dx, cy+dy, index)
+			}
+		}
+	}
+}
+
+// drawLine draws a one pixel wide line with Bresenham's algorithm.
+func drawLine(img *image.Paletted, from, to Point, index uint8) {
+	x0, y0 := int(math.Round(from.X)), int(math.Round(from.Y))
+	x1, y1 := int(math.Round(to.X)), int(math.Round(to.Y))
+	dx, dy := x1-x0, -(y1 - y0)
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		dx, stepX = -dx, -1
+	}
+	if dy > 0 {
+		dy, stepY = -dy, -1
+	}
+	err := dx + dy
+	for {
+		img.SetColorIndex(x0, y0, index)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += stepX
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += stepY
+		}
+	}
+}
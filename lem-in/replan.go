@@ -0,0 +1,584 @@
+package lemin
+
+import (
+	"container/list"
+	"sort"
+)
+
+// reservations records which ant holds each room at the end of every turn and which
+// tunnels are crossed during every turn, so routes planned at different times never
+// put two ants in a room or through a tunnel at once. Depots, like the start and end
+// rooms, hold any number of ants.
+type reservations struct {
+	depots   map[string]bool
+	rooms    map[string]map[int]int  // Ant holding the room at the end of each turn
+	tunnels  map[string]map[int]bool // Turns each tunnel is crossed in
+	stuck    map[string]int          // Ant that can't leave a room anymore, holding it for good
+	lastTurn int                     // Last turn anything is reserved in
+}
+
+func newReservations(depots ...string) *reservations {
+	r := &reservations{
+		depots:  make(map[string]bool),
+		rooms:   make(map[string]map[int]int),
+		tunnels: make(map[string]map[int]bool),
+		stuck:   make(map[string]int),
+	}
+	for _, room := range depots {
+		r.depots[room] = true
+	}
+	return r
+}
+
+// roomFree reports whether the ant can be in the room at the end of the turn.
+func (r *reservations) roomFree(room string, turn, ant int) bool {
+	if r.depots[room] {
+		return true
+	}
+	if holder, ok := r.stuck[room]; ok && holder != ant {
+		return false
+	}
+	holder, ok := r.rooms[room][turn]
+	return !ok || holder == ant
+}
+
+func (r *reservations) tunnelFree(a, b string, turn int) bool {
+	return !r.tunnels[tunnelKey(a, b)][turn]
+}
+
+// reserve books the rooms and tunnels of a track from the given turn on. An ant
+// taking speed turns per tunnel crosses it during the speed turns up to its move.
+func (r *reservations) reserve(ant int, track []string, from, speed int) {
+	for t := from; t < len(track); t++ {
+		if room := track[t]; !r.depots[room] {
+			if r.rooms[room] == nil {
+				r.rooms[room] = make(map[int]int)
+			}
+			r.rooms[room][t] = ant
+		}
+		if t > 0 && track[t] != track[t-1] {
+			key := tunnelKey(track[t-1], track[t])
+			if r.tunnels[key] == nil {
+				r.tunnels[key] = make(map[int]bool)
+			}
+			for crossing := t - speed + 1; crossing <= t; crossing++ {
+				r.tunnels[key][crossing] = true
+			}
+		}
+		if t > r.lastTurn {
+			r.lastTurn = t
+		}
+	}
+}
+
+// release frees what reserve booked for the track from the given turn on.
+func (r *reservations) release(track []string, from, speed int) {
+	for t := from; t < len(track); t++ {
+		delete(r.rooms[track[t]], t)
+		if t > 0 && track[t] != track[t-1] {
+			for crossing := t - speed + 1; crossing <= t; crossing++ {
+				delete(r.tunnels[tunnelKey(track[t-1], track[t])], crossing)
+			}
+		}
+	}
+}
+
+// planner keeps the room of every ant at the end of every turn and changes the
+// routes of the ants when tunnels close and rooms fail.
+type planner struct {
+	ants   *Graph          // The map as given, for the attributes of the ants
+	graph  *Graph          // The map as it is now, without the closed tunnels
+	goal   string          // Room the ants finish in
+	failed map[string]bool // Rooms that can't be entered anymore
+	tracks [][]string      // Room of each ant at the end of each turn, from turn 0, by ant number - 1
+	booked *reservations
+}
+
+// Plan returns the moves of each turn with the attributes of the ants honored and
+// the events of the graph applied, and the number of ants that can't reach the end room.
+// When an event breaks the route of an ant, the ants still in the start room are given
+// new paths from the remaining map and the ants on their way are routed from the room
+// they are in.
+func Plan(graph *Graph, paths *Paths) ([][]Move, int) {
+	p := newPlanner(graph, []string{graph.Start, graph.End})
+	if graph.HasAntAttributes() {
+		p.dispatch(p.all(), 1, paths)
+	} else {
+		// The usual schedule, as SimulateAnts prints it.
+		for t, turn := range Schedule(paths, graph.Ants) {
+			for _, move := range turn {
+				p.moveTo(move.Ant, t+1, move.Room)
+			}
+		}
+		for ant, track := range p.tracks {
+			p.booked.reserve(ant+1, track, 1, 1)
+		}
+	}
+
+	events := append([]Event(nil), graph.Events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Turn < events[j].Turn })
+	for _, event := range events {
+		p.apply(event)
+	}
+	return p.turns()
+}
+
+// newPlanner returns a planner for ants going through the stops in order, all of them
+// in the first stop before the first turn. The start and end rooms hold any number of
+// ants, the other stops one at a time.
+func newPlanner(graph *Graph, stops []string) *planner {
+	p := &planner{
+		ants:   graph,
+		graph:  CloneGraph(graph),
+		goal:   stops[len(stops)-1],
+		failed: make(map[string]bool),
+		tracks: make([][]string, graph.Ants),
+		booked: newReservations(graph.Start, graph.End),
+	}
+	for i := range p.tracks {
+		p.tracks[i] = []string{stops[0]}
+	}
+	return p
+}
+
+// all returns the number of every ant.
+func (p *planner) all() []int {
+	ants := make([]int, len(p.tracks))
+	for i := range ants {
+		ants[i] = i + 1
+	}
+	return ants
+}
+
+// turns returns the moves of each turn along the tracks, and the number of ants that
+// don't reach the goal.
+func (p *planner) turns() ([][]Move, int) {
+	var turns [][]Move
+	stranded := 0
+	for ant, track := range p.tracks {
+		for t := 1; t < len(track); t++ {
+			for len(turns) < t {
+				turns = append(turns, nil)
+			}
+			if track[t] != track[t-1] {
+				turns[t-1] = append(turns[t-1], Move{ant + 1, track[t]})
+			}
+		}
+		if track[len(track)-1] != p.goal {
+			stranded++
+		}
+	}
+	for len(turns) > 0 && len(turns[len(turns)-1]) == 0 {
+		turns = turns[:len(turns)-1]
+	}
+	return turns, stranded
+}
+
+// moveTo puts the ant in the room at the end of the turn, keeping it in its
+// previous room during the turns before.
+func (p *planner) moveTo(ant, turn int, room string) {
+	track := p.tracks[ant-1]
+	for len(track) < turn {
+		track = append(track, track[len(track)-1])
+	}
+	p.tracks[ant-1] = append(track, room)
+}
+
+// apply changes the map at the start of the event's turn and reroutes the ants it affects.
+func (p *planner) apply(event Event) {
+	switch event.Kind {
+	case EventClose:
+		if p.graph.Rooms[event.A].Edges[event.B] == 0 {
+			return
+		}
+		RemoveTunnel(p.graph, event.A, event.B)
+	case EventFail:
+		if p.failed[event.A] {
+			return
+		}
+		p.failed[event.A] = true
+	}
+
+	// Rerouting can leave an ant stuck in a room other ants meant to go through,
+	// so look again until every route holds.
+	turn := event.Turn
+	for {
+		var moving []int
+		waitingAffected := false
+		deadline := p.deadline()
+		for i := range p.tracks {
+			if ant := i + 1; p.broken(ant, turn, deadline) {
+				if p.roomAt(ant, turn-1) == p.graph.Start {
+					waitingAffected = true
+				} else {
+					moving = append(moving, ant)
+				}
+			}
+		}
+		if len(moving) == 0 && !waitingAffected {
+			return
+		}
+
+		// Every ant planned again gives up its route first, so the old routes aren't in the way.
+		var waiting []int
+		if waitingAffected {
+			waiting = p.releaseWaiting(turn)
+		}
+		for _, ant := range moving {
+			p.booked.release(p.tracks[ant-1], turn, p.ants.Speed(ant))
+			p.tracks[ant-1] = p.tracks[ant-1][:turn]
+		}
+		// Route the ants on their way from the room they are in.
+		for _, ant := range moving {
+			p.route(ant, turn)
+		}
+		if waitingAffected {
+			p.sendWaiting(waiting, turn)
+		}
+	}
+}
+
+// roomAt returns the room the ant is in at the end of the turn.
+func (p *planner) roomAt(ant, turn int) string {
+	track := p.tracks[ant-1]
+	if turn >= len(track) {
+		return track[len(track)-1]
+	}
+	return track[turn]
+}
+
+// broken reports whether the ant's track crosses a closed tunnel, enters a failed room
+// or a room another ant is stuck in from the given turn on, or gets an ant without
+// priority to the end room then and before the deadline.
+func (p *planner) broken(ant, turn, deadline int) bool {
+	track := p.tracks[ant-1]
+	if arrival := len(track) - 1; !p.ants.Priority[ant] && arrival >= turn && arrival < deadline && track[arrival] == p.goal {
+		return true
+	}
+	for t := turn; t < len(track); t++ {
+		from, to := track[t-1], track[t]
+		if from == to {
+			continue
+		}
+		if holder, stuck := p.booked.stuck[to]; p.failed[to] || p.graph.Rooms[from].Edges[to] == 0 || stuck && holder != ant {
+			return true
+		}
+	}
+	return false
+}
+
+// route finds the quickest way for the ant from the room it is in at the end of the
+// turn before, moving or waiting each turn around the reservations of the other ants.
+// An ant with no way left stays where it is for good.
+func (p *planner) route(ant, turn int) bool {
+	track := p.tracks[ant-1]
+	for len(track) < turn {
+		track = append(track, track[len(track)-1])
+	}
+	from := track[turn-1]
+	if !p.reachable(from) {
+		p.strand(ant, from)
+		return false
+	}
+
+	// Breadth-first search over (room, turn), where levels[i] holds the rooms the ant
+	// can be in at the end of turn turn-1+i, each with the room it came from. A slow
+	// ant keeps its room while going through a tunnel, so its moves span two levels.
+	// Past the last reserved turn nothing is in the way, so the search ends before
+	// horizon if the end is reachable.
+	speed := p.ants.Speed(ant)
+	deadline := 0
+	if !p.ants.Priority[ant] {
+		deadline = p.deadline()
+	}
+	horizon := p.booked.lastTurn + speed*len(p.graph.Rooms) + 1
+	levels := []map[string]string{{from: ""}}
+	for i := 0; turn-1+i < horizon; i++ {
+		for len(levels) < i+speed+1 {
+			levels = append(levels, make(map[string]string))
+		}
+		t := turn + i // Turn the next move starts in
+		for room := range levels[i] {
+			if _, seen := levels[i+1][room]; !seen && p.booked.roomFree(room, t, ant) {
+				levels[i+1][room] = room // Wait
+			}
+			if speed > 1 && !p.booked.roomFree(room, t, ant) {
+				continue // A slow ant holds its room while going through the tunnel
+			}
+			arrival := t + speed - 1
+			for neighbor := range p.graph.Rooms[room].Edges {
+				if _, seen := levels[i+speed][neighbor]; seen || p.failed[neighbor] || !p.booked.roomFree(neighbor, arrival, ant) {
+					continue
+				}
+				if neighbor == p.graph.End && arrival < deadline {
+					continue
+				}
+				free := true
+				for crossing := t; crossing <= arrival && free; crossing++ {
+					free = p.booked.tunnelFree(room, neighbor, crossing)
+				}
+				if free {
+					levels[i+speed][neighbor] = room
+				}
+			}
+		}
+		if _, ok := levels[i+1][p.graph.End]; ok {
+			p.tracks[ant-1] = append(track[:turn], p.unwind(levels[:i+2], speed)...)
+			p.booked.reserve(ant, p.tracks[ant-1], turn, speed)
+			return true
+		}
+	}
+	p.strand(ant, from)
+	return false
+}
+
+// unwind returns the room of the ant at the end of each turn after the first level,
+// walking back from the end room in the last level.
+func (p *planner) unwind(levels []map[string]string, speed int) []string {
+	rooms := make([]string, len(levels)-1)
+	room := p.graph.End
+	for i := len(levels) - 1; i > 0; {
+		rooms[i-1] = room
+		prev := levels[i][room]
+		if prev == room {
+			i--
+			continue
+		}
+		for step := 1; step < speed; step++ {
+			rooms[i-1-step] = prev // Still in its room while going through the tunnel
+		}
+		i -= speed
+		room = prev
+	}
+	return rooms
+}
+
+// reachable reports whether the end room can be reached from the room.
+func (p *planner) reachable(from string) bool {
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		if room == p.graph.End {
+			return true
+		}
+		for neighbor := range p.graph.Rooms[room].Edges {
+			if !seen[neighbor] && !p.failed[neighbor] {
+				seen[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return false
+}
+
+// strand keeps the ant in the room for good.
+func (p *planner) strand(ant int, room string) {
+	if room != p.graph.Start {
+		p.booked.stuck[room] = ant
+	}
+}
+
+// releaseWaiting cancels the routes of the ants still in the start room at the
+// given turn and returns them.
+func (p *planner) releaseWaiting(turn int) []int {
+	var waiting []int
+	for i, track := range p.tracks {
+		if p.roomAt(i+1, turn-1) == p.graph.Start {
+			p.booked.release(track, turn, p.ants.Speed(i+1))
+			if len(track) > turn {
+				p.tracks[i] = track[:turn]
+			}
+			waiting = append(waiting, i+1)
+		}
+	}
+	return waiting
+}
+
+// sendWaiting gives the waiting ants new paths, found by the solver on the map as it
+// is now, and sends each along its path as soon as it doesn't run into the other ants.
+func (p *planner) sendWaiting(waiting []int, turn int) {
+	remaining := CloneGraph(p.graph)
+	remaining.Ants = len(waiting)
+	for room := range p.failed {
+		removeRoom(remaining, room)
+	}
+	paths := Solve(remaining)
+	if paths == nil {
+		for _, ant := range waiting {
+			p.route(ant, turn)
+		}
+		return
+	}
+	p.dispatch(waiting, turn, paths)
+}
+
+// dispatch sends the ants from the first room of the paths, where each waits from the
+// end of its track and from the given turn on, along the path it reaches the last room
+// quickest by. With paths for several legs, each ant goes through them in turn and waits
+// in the room between two legs until it can leave it. Ants with priority go first and,
+// when the paths lead to the goal, the others can't arrive before the last of them;
+// slow ants go first among the ants alike, as they take the longest.
+func (p *planner) dispatch(ants []int, turn int, legs ...*Paths) {
+	order := append([]int(nil), ants...)
+	rank := func(ant int) int {
+		r := 0
+		if !p.ants.Priority[ant] {
+			r += 2
+		}
+		if !p.ants.Slow[ant] {
+			r++
+		}
+		return r
+	}
+	ready := func(ant int) int {
+		if len(p.tracks[ant-1]) > turn {
+			return len(p.tracks[ant-1])
+		}
+		return turn
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if rank(order[i]) != rank(order[j]) {
+			return rank(order[i]) < rank(order[j])
+		}
+		return ready(order[i]) < ready(order[j])
+	})
+
+	routes := make([][][]string, len(legs))
+	for k, paths := range legs {
+		for _, path := range paths.AllPaths {
+			routes[k] = append(routes[k], listRooms(path))
+		}
+	}
+	last := routes[len(routes)-1]
+	final := last[0][len(last[0])-1] == p.goal
+	deadline := 0
+	if final {
+		deadline = p.deadline()
+	}
+	for _, ant := range order {
+		earliest := 0
+		if final && !p.ants.Priority[ant] {
+			earliest = deadline
+		}
+		from := ready(ant)
+		chosen, arrival, ok := p.plan(ant, from, 0, routes, earliest)
+		if !ok {
+			p.route(ant, from)
+			continue
+		}
+		speed := p.ants.Speed(ant)
+		for _, leg := range chosen {
+			for j := 1; j < len(leg.rooms); j++ {
+				p.moveTo(ant, leg.depart+speed*j-1, leg.rooms[j])
+			}
+		}
+		p.booked.reserve(ant, p.tracks[ant-1], from, speed)
+		if final && p.ants.Priority[ant] && arrival > deadline {
+			deadline = arrival
+		}
+	}
+}
+
+// leg is the route an ant takes between two stops and the turn it leaves the first in.
+type leg struct {
+	rooms  []string
+	depart int
+}
+
+// plan returns the legs taking the ant along one of the routes of each leg in turn,
+// leaving from the given turn on, that get it to the last room quickest, no earlier
+// than the turn earliest, with the turn it arrives. hold is the turn the ant reached
+// the first room in when it has to be kept there until it leaves, or 0.
+func (p *planner) plan(ant, turn, hold int, routes [][][]string, earliest int) ([]leg, int, bool) {
+	var best []leg
+	bestArrival := 0
+	for _, rooms := range routes[0] {
+		if len(routes) == 1 {
+			if depart, arrival, ok := p.fit(ant, turn, rooms, earliest, hold); ok && (best == nil || arrival < bestArrival) {
+				best, bestArrival = []leg{{rooms, depart}}, arrival
+			}
+			continue
+		}
+		// Leave as soon as the rest of the way fits, which it does once nothing is
+		// booked anymore.
+		for from := turn; ; {
+			depart, arrival, ok := p.fit(ant, from, rooms, 0, hold)
+			if !ok {
+				break
+			}
+			if rest, end, ok := p.plan(ant, arrival+1, arrival, routes[1:], earliest); ok {
+				if best == nil || end < bestArrival {
+					best, bestArrival = append([]leg{{rooms, depart}}, rest...), end
+				}
+				break
+			}
+			from = depart + 1
+		}
+	}
+	return best, bestArrival, best != nil
+}
+
+// deadline returns the last turn an ant with priority reaches the goal in, before
+// which the other ants can't arrive.
+func (p *planner) deadline() int {
+	deadline := 0
+	for ant, track := range p.tracks {
+		if p.ants.Priority[ant+1] && track[len(track)-1] == p.goal && len(track)-1 > deadline {
+			deadline = len(track) - 1
+		}
+	}
+	return deadline
+}
+
+// fit returns the earliest turn from the given one the ant can leave the first room
+// to go along the rooms without waiting on the way and reach the last no earlier than
+// the turn earliest, with the turn it arrives. With hold set, the ant is in the first
+// room from that turn on and has to be able to stay there until it leaves.
+func (p *planner) fit(ant, turn int, rooms []string, earliest, hold int) (int, int, bool) {
+	speed := p.ants.Speed(ant)
+	length := speed * (len(rooms) - 1) // Turns from leaving to arriving
+	depart := turn
+	if depart < earliest-length+1 {
+		depart = earliest - length + 1
+	}
+	last := p.booked.lastTurn + 1 // Nothing is in the way from then on
+	if last < depart {
+		last = depart
+	}
+	for held := hold; depart <= last || depart <= earliest; depart++ {
+		// Waiting longer doesn't help once another ant needs the room.
+		for ; hold > 0 && held <= depart+speed-2; held++ {
+			if !p.booked.roomFree(rooms[0], held, ant) {
+				return 0, 0, false
+			}
+		}
+		fits := true
+		for j := 1; j < len(rooms) && fits; j++ {
+			enter := depart + speed*j - 1
+			for t := enter - speed + 1; t <= enter && fits; t++ {
+				fits = p.booked.tunnelFree(rooms[j-1], rooms[j], t)
+			}
+			if j == len(rooms)-1 {
+				break
+			}
+			for t := enter; t < enter+speed && fits; t++ {
+				fits = p.booked.roomFree(rooms[j], t, ant)
+			}
+		}
+		if fits {
+			return depart, depart + length - 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// listRooms returns the rooms of a path in order.
+func listRooms(path *list.List) []string {
+	rooms := make([]string, 0, path.Len())
+	for e := path.Front(); e != nil; e = e.Next() {
+		rooms = append(rooms, e.Value.(string))
+	}
+	return rooms
+}
@@ -0,0 +1,79 @@
+package lemin
+
+import "testing"
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		line string
+		want Event
+		ok   bool
+	}{
+		{"##close a-b at 5", Event{Kind: EventClose, A: "a", B: "b", Turn: 5}, true},
+		{"##fail room7 at 10", Event{Kind: EventFail, A: "room7", Turn: 10}, true},
+		{"##close ab at 5", Event{}, false},
+		{"##fail a at 0", Event{}, false},
+		{"##fail a on 3", Event{}, false},
+		{"##fail a at", Event{}, false},
+	}
+	for _, test := range tests {
+		event, err := ParseEvent(test.line)
+		if (err == nil) != test.ok || event != test.want {
+			t.Errorf("%q: %+v, %v", test.line, event, err)
+		}
+		if test.ok && event.String() != test.line {
+			t.Errorf("%q written back as %q", test.line, event)
+		}
+	}
+}
+
+func TestPlanEvents(t *testing.T) {
+	const twoPaths = "4\n##start\ns 0 0\na 1 0\nb 1 1\n##end\ne 2 0\ns-a\na-e\ns-b\nb-e\n"
+	tests := []struct {
+		name     string
+		content  string
+		turns    int
+		stranded int
+	}{
+		{"no event", twoPaths, 3, 0},
+		{"tunnel closed ahead of an ant", twoPaths + "##close a-e at 2\n", 5, 0},
+		{"room failed before the first turn", twoPaths + "##fail a at 1\n", 5, 0},
+		{"comment that reads like an event", twoPaths + "#close a-e at 2\n", 3, 0},
+		{"room without tunnels failed", twoPaths + "x 3 0\n##fail x at 1\n", 3, 0},
+		{"tunnel closed once the ants are through", twoPaths + "##close s-a at 9\n", 3, 0},
+		{"ant rerouted from the room it is in", "4\n##start\ns 0 0\na 1 0\nb 1 1\nc 2 1\n##end\ne 2 0\ns-a\na-e\ns-b\nb-c\nc-e\na-b\n##close a-e at 2\n", 6, 0},
+		{"only path cut", "3\n##start\ns 0 0\na 1 0\n##end\ne 2 0\ns-a\na-e\n##fail a at 2\n", 2, 2},
+	}
+	for _, test := range tests {
+		graph, _, err := ParseMap(test.content)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		turns, stranded := Plan(graph, Solve(graph))
+		if len(turns) != test.turns || stranded != test.stranded {
+			t.Errorf("%s: %d turns and %d stranded ants, want %d and %d", test.name, len(turns), stranded, test.turns, test.stranded)
+		}
+		if stranded == 0 {
+			if err := ValidateTurns(graph, turns, Stops(graph, false)); err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+		}
+	}
+}
+
+func TestEventsOnMissingRoomsAndTunnels(t *testing.T) {
+	const twoPaths = "4\n##start\ns 0 0\na 1 0\nb 1 1\n##end\ne 2 0\nx 3 0\ns-a\na-e\ns-b\nb-e\n"
+	tests := []struct {
+		event string
+		err   string
+	}{
+		{"##fail y at 1", "can't fail y, there's no such room"},
+		{"##fail s at 1", "can't fail the start or end room"},
+		{"##close a-b at 1", "can't close a-b, there's no such tunnel"},
+		{"##close x-a at 1", "can't close x-a, there's no such tunnel"},
+	}
+	for _, test := range tests {
+		if _, _, err := ParseMap(twoPaths + test.event + "\n"); err == nil || err.Error() != test.err {
+			t.Errorf("%s: error %v, want %s", test.event, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package lemin
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Largest map accepted by the web visualiser, in bytes.
+const maxUploadSize = 10 << 20
+
+// Most ants in a map accepted by the web visualiser, as every move of every ant is
+// kept and sent to the browser.
+const maxServeAnts = 10000
+
+//go:embed web/index.html
+var indexPage []byte
+
+// Solution is the JSON form of a solved map sent to the web visualiser.
+type Solution struct {
+	Start   string         `json:"start"`
+	End     string         `json:"end"`
+	Ants    int            `json:"ants"`
+	Rooms   []SolutionRoom `json:"rooms"`
+	Tunnels [][2]string    `json:"tunnels"`
+	Paths   []SolutionPath `json:"paths"`
+	Turns   [][]Move       `json:"turns"`
+}
+
+// SolutionRoom is a room and its position in a 1000x1000 area.
+type SolutionRoom struct {
+	Name string  `json:"name"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// SolutionPath is a path and the number of ants sent along it.
+type SolutionPath struct {
+	Rooms []string `json:"rooms"`
+	Ants  int      `json:"ants"`
+}
+
+// RunServe implements the `serve` command.
+func RunServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	port := flags.Int("port", 8080, "port to listen on")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		fmt.Println("Usage: program serve [--port=N]")
+		os.Exit(1)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", serveIndex)
+	mux.HandleFunc("/api/solve", serveSolve)
+
+	addr := fmt.Sprintf("127.0.0.1:%d", *port)
+	fmt.Printf("Serving the visualiser on http://%s\n", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexPage)
+}
+
+// serveSolve solves the map posted as the request body.
+func serveSolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSONError(w, http.StatusMethodNotAllowed, "use POST with the map as the body")
+		return
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err != nil {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "the map is too large")
+		return
+	}
+	graph, _, err := ParseMap(string(content))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if graph.Ants > maxServeAnts {
+		writeJSONError(w, http.StatusUnprocessableEntity, fmt.Sprintf("the map has more than %d ants", maxServeAnts))
+		return
+	}
+	solution, err := SolveMap(graph)
+	if err != nil {
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(solution)
+}
+
+// SolveMap solves the graph and gathers everything the web visualiser draws.
+func SolveMap(graph *Graph) (*Solution, error) {
+	paths := Solve(graph)
+	if paths == nil {
+		return nil, fmt.Errorf("No paths found")
+	}
+	turns, _, err := PlanTurns(graph, paths, Stops(graph, false))
+	if err != nil {
+		return nil, err
+	}
+	solution := &Solution{
+		Start: graph.Start,
+		End:   graph.End,
+		Ants:  graph.Ants,
+		Turns: turns,
+	}
+
+	positions := Layout(graph, 1000, 1000, 20)
+	for _, name := range sortedRooms(graph) {
+		p := positions[name]
+		solution.Rooms = append(solution.Rooms, SolutionRoom{name, p.X, p.Y})
+		for neighbor := range graph.Rooms[name].Edges {
+			if name < neighbor {
+				solution.Tunnels = append(solution.Tunnels, [2]string{name, neighbor})
+			}
+		}
+	}
+	ants := pathAnts(paths, antPaths(graph, paths, turns))
+	for i, path := range paths.AllPaths {
+		var rooms []string
+		for e := path.Front(); e != nil; e = e.Next() {
+			rooms = append(rooms, e.Value.(string))
+		}
+		solution.Paths = append(solution.Paths, SolutionPath{rooms, ants[i]})
+	}
+	return solution, nil
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
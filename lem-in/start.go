@@ -2,6 +2,7 @@ package lemin
 
 import (
 	"container/list"
+	"flag"
 	"fmt"
 	"os"
 )
@@ -13,12 +14,23 @@ type Graph struct {
 	Rooms      map[string]*Node
 	Exits      *list.List
 	Start, End string
+	Pickup     string // Room the ants must go through before the end room, if any
 	Ants       int
+	Corridors  map[[2]string][]string // Rooms hidden inside each contracted tunnel, in walking order
+	Coords     map[string]Coord       // Declared position of each room
+	Events     []Event                // Tunnel closures and room failures, in input order
+	Slow       map[int]bool           // Ants taking two turns per tunnel, by number
+	Priority   map[int]bool           // Ants that must arrive first, by number
+}
+
+// Coord is the position declared for a room in the input file.
+type Coord struct {
+	X, Y int
 }
 
 // Node represents a room in the graph.
 type Node struct {
-	Edges             map[string]bool
+	Edges             map[string]int // Length of the tunnel to each neighbor
 	Prev              string
 	EdgeIn, EdgeOut   string
 	PriceIn, PriceOut int
@@ -33,12 +45,101 @@ type Paths struct {
 	Assignment           []int // Number of ants assigned to each path
 }
 
+// commands maps each subcommand name to its entry point.
+var commands = map[string]func(args []string){
+	"lint":     RunLint,
+	"analyze":  RunAnalyze,
+	"suggest":  RunSuggest,
+	"sweep":    RunSweep,
+	"render":   RunRender,
+	"svg":      RunSVG,
+	"serve":    RunServe,
+	"validate": RunValidate,
+}
+
 func Run() {
-	graph := GetGraph()
-	paths := ComputePaths(graph)
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+
+	flags := flag.NewFlagSet("lem-in", flag.ExitOnError)
+	tui := flags.Bool("tui", false, "step through the solution in the terminal")
+	stream := flags.Bool("stream", false, "flush every turn as soon as it is computed")
+	traceAnts := flags.Bool("trace-ants", false, "print the journey of every ant instead of the turns")
+	stats := flags.Bool("stats", false, "print occupancy statistics per turn, room and path instead of the turns")
+	statsFormat := flags.String("stats-format", "table", "format of --stats: table or csv")
+	debugSolver := flags.Bool("debug-solver", false, "print every iteration of the solver instead of the turns")
+	debugFormat := flags.String("debug-format", "text", "format of --debug-solver: text or json")
+	explain := flags.Bool("explain", false, "print every candidate path set and which one was chosen instead of the turns")
+	roundTrip := flags.Bool("round-trip", false, "send the ants back to the start room from the end room")
+	flags.Parse(os.Args[1:])
+	if *statsFormat != "table" && *statsFormat != "csv" {
+		fmt.Println("--stats-format must be table or csv")
+		os.Exit(1)
+	}
+	if *debugFormat != "text" && *debugFormat != "json" {
+		fmt.Println("--debug-format must be text or json")
+		os.Exit(1)
+	}
+
+	graph, content := GetGraph(flags.Args())
+	stops := Stops(graph, *roundTrip)
+	if (*debugSolver || *explain) && Planned(graph, stops) {
+		fmt.Println("--debug-solver and --explain can't be combined with events, slow or priority ants, a pickup room or --round-trip")
+		os.Exit(1)
+	}
+	paths := Solve(graph)
 	if paths == nil {
 		fmt.Println("No paths found")
 		os.Exit(1)
 	}
-	SimulateAnts(paths, graph.Ants)
+	if *debugSolver {
+		PrintSolverTrace(TraceSolver(graph), *debugFormat == "json")
+		return
+	}
+	if *explain {
+		PrintExplanation(ExplainPaths(graph), graph.Ants)
+		return
+	}
+	if !*tui && !*traceAnts && !*stats && !Planned(graph, stops) {
+		// Printed as they are scheduled, so the turns never have to fit in memory.
+		fmt.Printf("%s\n\n", content)
+		SimulateAnts(paths, graph.Ants, *stream)
+		return
+	}
+
+	turns, stranded, err := PlanTurns(graph, paths, stops)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	switch {
+	case *tui:
+		err = RunTUI(graph, paths, turns)
+	case *traceAnts:
+		PrintTraces(TraceAnts(graph, paths, turns, stops))
+	case *stats:
+		PrintStats(ComputeStats(graph, paths, turns, stops), *statsFormat == "csv")
+	default:
+		fmt.Printf("%s\n\n", content)
+		PrintTurns(turns, *stream)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if stranded > 0 {
+		fmt.Printf("\n%d ants can't reach the end room\n", stranded)
+		os.Exit(1)
+	}
+}
+
+// Solve reduces a copy of the graph and computes the best set of paths for it.
+func Solve(graph *Graph) *Paths {
+	clone := CloneGraph(graph)
+	ReduceGraph(clone)
+	return ComputePaths(clone)
 }
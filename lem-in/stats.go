@@ -0,0 +1,161 @@
+package lemin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TurnStats counts where the ants are after a turn.
+type TurnStats struct {
+	Turn      int
+	InTransit int
+	AtStart   int
+	Arrived   int
+}
+
+// RoomStats is the number of turns a room held an ant, out of all the turns.
+type RoomStats struct {
+	Room        string
+	Occupied    int
+	Utilization float64
+}
+
+// PathStats is the ants the solver assigns to a path and the turn the last ant
+// leaving along it arrived.
+type PathStats struct {
+	Path        int
+	Moves       int
+	Ants        int
+	LastArrival int
+}
+
+// Stats gathers the occupancy of the solution turn by turn, room by room and path by path.
+type Stats struct {
+	Turns []TurnStats
+	Rooms []RoomStats
+	Paths []PathStats
+}
+
+// ComputeStats replays the turns to count where the ants are after each turn. An ant
+// is in a room from the turn it enters it to the turn before it leaves, and in transit
+// from the turn it starts through its first tunnel, which a slow ant does a turn before
+// it moves. It has arrived from its last move on if that move takes it to the last stop.
+func ComputeStats(graph *Graph, paths *Paths, turns [][]Move, stops []string) *Stats {
+	totalTurns := len(turns)
+	first := make([]int, graph.Ants+1)    // Turn of the first move of each ant, by number
+	last := make([]int, graph.Ants+1)     // Turn of the last move of each ant
+	rooms := make([]string, graph.Ants+1) // Room each ant is in after its last move
+	occupied := make(map[string]int)
+	for t, moves := range turns {
+		for _, move := range moves {
+			if first[move.Ant] == 0 {
+				first[move.Ant] = t + 1
+			} else {
+				occupied[rooms[move.Ant]] += t + 1 - last[move.Ant]
+			}
+			last[move.Ant], rooms[move.Ant] = t+1, move.Room
+		}
+	}
+
+	// Ants leaving the start room and arriving in each turn, summed up turn by turn below
+	leaving := make([]int, totalTurns+2)
+	arriving := make([]int, totalTurns+2)
+	goal := stops[len(stops)-1]
+	for ant := 1; ant <= graph.Ants; ant++ {
+		if first[ant] == 0 {
+			continue
+		}
+		leaving[first[ant]-graph.Speed(ant)+1]++
+		occupied[rooms[ant]] += totalTurns + 1 - last[ant]
+		if rooms[ant] == goal {
+			arriving[last[ant]]++
+		}
+	}
+
+	stats := &Stats{Turns: make([]TurnStats, totalTurns)}
+	left, arrived := 0, 0
+	for t := range stats.Turns {
+		left += leaving[t+1]
+		arrived += arriving[t+1]
+		stats.Turns[t] = TurnStats{Turn: t + 1, InTransit: left - arrived, AtStart: graph.Ants - left, Arrived: arrived}
+	}
+
+	for _, room := range sortedRooms(graph) {
+		if room == graph.Start || room == graph.End {
+			continue
+		}
+		utilization := 0.0
+		if totalTurns > 0 {
+			utilization = float64(occupied[room]) / float64(totalTurns)
+		}
+		stats.Rooms = append(stats.Rooms, RoomStats{room, occupied[room], utilization})
+	}
+
+	paths.distributeAnts(graph.Ants)
+	for i, path := range paths.AllPaths {
+		stats.Paths = append(stats.Paths, PathStats{Path: i + 1, Moves: path.Len() - 1, Ants: paths.Assignment[i]})
+	}
+	for i, path := range antPaths(graph, paths, turns) {
+		if ant := i + 1; path >= 0 && rooms[ant] == goal && last[ant] > stats.Paths[path].LastArrival {
+			stats.Paths[path].LastArrival = last[ant]
+		}
+	}
+	return stats
+}
+
+// PrintStats prints the statistics as aligned tables, or as CSV sections separated by
+// blank lines when csv is set.
+func PrintStats(stats *Stats, csv bool) {
+	var rows [][]string
+	for _, t := range stats.Turns {
+		rows = append(rows, []string{strconv.Itoa(t.Turn), strconv.Itoa(t.InTransit), strconv.Itoa(t.AtStart), strconv.Itoa(t.Arrived)})
+	}
+	printTable([]string{"turn", "in_transit", "at_start", "arrived"}, rows, csv)
+	fmt.Println()
+
+	rows = nil
+	for _, r := range stats.Rooms {
+		rows = append(rows, []string{r.Room, strconv.Itoa(r.Occupied), strconv.FormatFloat(r.Utilization, 'f', 2, 64)})
+	}
+	printTable([]string{"room", "occupied", "utilization"}, rows, csv)
+	fmt.Println()
+
+	rows = nil
+	for _, p := range stats.Paths {
+		rows = append(rows, []string{strconv.Itoa(p.Path), strconv.Itoa(p.Moves), strconv.Itoa(p.Ants), strconv.Itoa(p.LastArrival)})
+	}
+	printTable([]string{"path", "moves", "ants", "last_arrival"}, rows, csv)
+}
+
+// printTable prints the rows under the header, either comma separated or in columns
+// with the first left-aligned and the others right-aligned.
+func printTable(header []string, rows [][]string, csv bool) {
+	if csv {
+		fmt.Println(strings.Join(header, ","))
+		for _, row := range rows {
+			fmt.Println(strings.Join(row, ","))
+		}
+		return
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i == 0 {
+				cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+			} else {
+				cells[i] = fmt.Sprintf("%*s", widths[i], cell)
+			}
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
@@ -0,0 +1,95 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	const chain = "##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\nb-e\n"
+	tests := []struct {
+		name     string
+		content  string
+		turns    []TurnStats
+		occupied map[string]int
+		paths    []PathStats
+	}{
+		{"chain", "2\n" + chain, []TurnStats{
+			{1, 1, 1, 0},
+			{2, 2, 0, 0},
+			{3, 1, 0, 1},
+			{4, 0, 0, 2},
+		}, map[string]int{"a": 2, "b": 2}, []PathStats{{1, 3, 2, 4}}},
+		{"slow ant waiting in each room", "1\n" + chain + "##slow 1\n", []TurnStats{
+			{1, 1, 0, 0},
+			{2, 1, 0, 0},
+			{3, 1, 0, 0},
+			{4, 1, 0, 0},
+			{5, 1, 0, 0},
+			{6, 0, 0, 1},
+		}, map[string]int{"a": 2, "b": 2}, []PathStats{{1, 3, 1, 6}}},
+		{"stranded ants", "2\n" + chain + "##close b-e at 3\n", []TurnStats{
+			{1, 1, 1, 0},
+			{2, 2, 0, 0},
+		}, map[string]int{"a": 2, "b": 1}, []PathStats{{1, 3, 2, 0}}},
+	}
+	for _, test := range tests {
+		graph, _, err := ParseMap(test.content)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		paths := Solve(graph)
+		stops := Stops(graph, false)
+		turns, _, err := PlanTurns(graph, paths, stops)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		stats := ComputeStats(graph, paths, turns, stops)
+		if !reflect.DeepEqual(stats.Turns, test.turns) {
+			t.Errorf("%s: turns %v, want %v", test.name, stats.Turns, test.turns)
+		}
+		for _, room := range stats.Rooms {
+			if room.Occupied != test.occupied[room.Room] {
+				t.Errorf("%s: room %s held an ant for %d turns, want %d", test.name, room.Room, room.Occupied, test.occupied[room.Room])
+			}
+		}
+		if !reflect.DeepEqual(stats.Paths, test.paths) {
+			t.Errorf("%s: paths %v, want %v", test.name, stats.Paths, test.paths)
+		}
+	}
+}
+
+func TestComputeStatsOnAuditExamples(t *testing.T) {
+	for _, file := range []string{"example00.txt", "example01.txt", "example02.txt", "example03.txt", "example04.txt", "example05.txt"} {
+		graph, _, err := ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths := Solve(graph)
+		turns := Schedule(paths, graph.Ants)
+		stats := ComputeStats(graph, paths, turns, Stops(graph, false))
+		for _, turn := range stats.Turns {
+			if turn.InTransit+turn.AtStart+turn.Arrived != graph.Ants {
+				t.Errorf("%s: %+v doesn't add up to %d ants", file, turn, graph.Ants)
+			}
+		}
+		if last := stats.Turns[len(stats.Turns)-1]; last.Arrived != graph.Ants {
+			t.Errorf("%s: %d ants arrived after the last turn, want %d", file, last.Arrived, graph.Ants)
+		}
+		// Scheduled ants never wait, so each move into a room other than the end room holds it for a turn
+		moves, held := 0, 0
+		for _, turn := range turns {
+			for _, move := range turn {
+				if move.Room != graph.End {
+					moves++
+				}
+			}
+		}
+		for _, room := range stats.Rooms {
+			held += room.Occupied
+		}
+		if held != moves {
+			t.Errorf("%s: rooms held ants for %d turns, want %d", file, held, moves)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package lemin
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Suggestion is a single tunnel change and the number of turns it gives.
+type Suggestion struct {
+	Add      bool // true to add the tunnel, false to remove it
+	From, To string
+	Turns    int
+	Saved    int
+}
+
+// RunSuggest implements the `suggest` command.
+func RunSuggest(args []string) {
+	flags := flag.NewFlagSet("suggest", flag.ExitOnError)
+	ants := flags.Int("ants", 0, "number of ants to optimize for (default: the map's)")
+	nearest := flags.Int("nearest", 3, "number of nearest rooms each room may be linked to")
+	top := flags.Int("top", 10, "maximum number of suggestions to print")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: program suggest [--ants=N] [--nearest=K] [--top=N] input_file")
+		os.Exit(1)
+	}
+
+	graph, _, err := ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if *ants > 0 {
+		graph.Ants = *ants
+	}
+	base := Solve(graph)
+	if base == nil {
+		fmt.Println("No paths found")
+		os.Exit(1)
+	}
+
+	suggestions := SuggestTunnels(graph, base, *nearest)
+	fmt.Printf("Turns for %d ants: %d\n", graph.Ants, base.Turns())
+	if len(suggestions) == 0 {
+		fmt.Println("No single tunnel change saves a turn")
+		return
+	}
+	for i, s := range suggestions {
+		if i == *top {
+			break
+		}
+		action := "remove"
+		if s.Add {
+			action = "add"
+		}
+		turns := "turns"
+		if s.Saved == 1 {
+			turns = "turn"
+		}
+		fmt.Printf("%s tunnel %s-%s to save %d %s (%d -> %d)\n", action, s.From, s.To, s.Saved, turns, base.Turns(), s.Turns)
+	}
+}
+
+// SuggestTunnels re-solves the graph for every candidate change and returns the ones
+// saving turns, best first. Candidates are tunnels from each room to its nearest
+// unlinked rooms by declared coordinates, and removals of the tunnels used by the
+// current paths, since removing an unused tunnel leaves the current paths available.
+func SuggestTunnels(graph *Graph, base *Paths, nearest int) []Suggestion {
+	var suggestions []Suggestion
+	try := func(add bool, a, b string) {
+		clone := CloneGraph(graph)
+		if add {
+			AddTunnel(clone, a, b)
+		} else {
+			RemoveTunnel(clone, a, b)
+		}
+		if paths := Solve(clone); paths != nil && paths.Turns() < base.Turns() {
+			suggestions = append(suggestions, Suggestion{add, a, b, paths.Turns(), base.Turns() - paths.Turns()})
+		}
+	}
+
+	for _, pair := range nearbyPairs(graph, nearest) {
+		try(true, pair[0], pair[1])
+	}
+	for _, pair := range usedTunnels(base) {
+		try(false, pair[0], pair[1])
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].Saved > suggestions[j].Saved })
+	return suggestions
+}
+
+// nearbyPairs returns each room with coordinates paired with its nearest rooms that it
+// isn't linked to yet, including rooms without any tunnel. Two rooms without tunnels
+// aren't paired, as a tunnel between them leads nowhere.
+func nearbyPairs(graph *Graph, nearest int) [][2]string {
+	var names []string
+	for name := range graph.Coords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	seen := make(map[string]bool)
+	var pairs [][2]string
+	for _, a := range names {
+		var others []string
+		for _, b := range names {
+			if b != a && !linked(graph, a, b) && (graph.Rooms[a] != nil || graph.Rooms[b] != nil) {
+				others = append(others, b)
+			}
+		}
+		sort.SliceStable(others, func(i, j int) bool {
+			return squaredDistance(graph.Coords[a], graph.Coords[others[i]]) < squaredDistance(graph.Coords[a], graph.Coords[others[j]])
+		})
+		for i := 0; i < nearest && i < len(others); i++ {
+			if key := tunnelKey(a, others[i]); !seen[key] {
+				seen[key] = true
+				pairs = append(pairs, [2]string{a, others[i]})
+			}
+		}
+	}
+	return pairs
+}
+
+// linked reports whether a tunnel joins the rooms.
+func linked(graph *Graph, a, b string) bool {
+	if node := graph.Rooms[a]; node != nil {
+		_, ok := node.Edges[b]
+		return ok
+	}
+	return false
+}
+
+// usedTunnels returns the tunnels walked by the given paths.
+func usedTunnels(paths *Paths) [][2]string {
+	var pairs [][2]string
+	for _, path := range paths.AllPaths {
+		for e := path.Front(); e.Next() != nil; e = e.Next() {
+			pairs = append(pairs, [2]string{e.Value.(string), e.Next().Value.(string)})
+		}
+	}
+	return pairs
+}
+
+func squaredDistance(a, b Coord) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,65 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestTunnels(t *testing.T) {
+	// A single corridor: linking rooms further along it saves the turns walked in between.
+	graph, _, err := ParseMap("1\n##start\ns 0 0\na 1 0\nb 2 0\nc 3 0\n##end\ne 4 0\ns-a\na-b\nb-c\nc-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Suggestion{
+		{true, "e", "s", 1, 3},
+		{true, "a", "e", 2, 2},
+		{true, "c", "s", 2, 2},
+		{true, "a", "c", 3, 1},
+		{true, "b", "e", 3, 1},
+		{true, "b", "s", 3, 1},
+	}
+	if suggestions := SuggestTunnels(graph, Solve(graph), 3); !reflect.DeepEqual(suggestions, want) {
+		t.Errorf("got %+v\nwant %+v", suggestions, want)
+	}
+}
+
+func TestNearbyPairsWithRoomsWithoutTunnels(t *testing.T) {
+	// x and y have no tunnel: each is paired with the linked rooms, but not with the other.
+	graph, _, err := ParseMap("1\n##start\ns 0 0\n##end\ne 2 0\nx 1 1\ny 1 2\ns-e\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"e", "x"}, {"s", "x"}, {"y", "e"}}
+	if pairs := nearbyPairs(graph, 1); !reflect.DeepEqual(pairs, want) {
+		t.Errorf("got %v, want %v", pairs, want)
+	}
+	if suggestions := SuggestTunnels(graph, Solve(graph), 3); len(suggestions) != 0 {
+		t.Errorf("got %+v, want nothing to save on a direct tunnel", suggestions)
+	}
+}
+
+func TestSuggestTunnelsOnAuditExamples(t *testing.T) {
+	for _, file := range []string{"example00.txt", "example01.txt", "example02.txt", "example03.txt", "example04.txt", "example05.txt"} {
+		graph, _, err := ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base := Solve(graph)
+		suggestions := SuggestTunnels(graph, base, 3)
+		for i, s := range suggestions {
+			if i > 0 && s.Saved > suggestions[i-1].Saved {
+				t.Errorf("%s: %+v comes after %+v", file, s, suggestions[i-1])
+			}
+			clone := CloneGraph(graph)
+			if s.Add {
+				AddTunnel(clone, s.From, s.To)
+			} else {
+				RemoveTunnel(clone, s.From, s.To)
+			}
+			if turns := Solve(clone).Turns(); turns != s.Turns || s.Saved != base.Turns()-turns || s.Saved <= 0 {
+				t.Errorf("%s: %+v, but the map changed that way takes %d turns instead of %d", file, s, turns, base.Turns())
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package lemin
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// RunSVG implements the `svg` command.
+func RunSVG(args []string) {
+	flags := flag.NewFlagSet("svg", flag.ExitOnError)
+	outPath := flags.String("out", "", "write the SVG to this file instead of stdout")
+	width := flags.Int("width", 800, "image width")
+	height := flags.Int("height", 600, "image height")
+	turnSeconds := flags.Float64("turn", 0.5, "seconds each turn lasts in the animation")
+	roundTrip := flags.Bool("round-trip", false, "send the ants back to the start room from the end room")
+	flags.Parse(args)
+	if flags.NArg() != 1 || *width < 100 || *height < 100 || *turnSeconds <= 0 {
+		fmt.Println("Usage: program svg [--out file.svg] [--width=W] [--height=H] [--turn=SECONDS] [--round-trip] input_file")
+		os.Exit(1)
+	}
+
+	graph, _, err := ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	paths := Solve(graph)
+	if paths == nil {
+		fmt.Println("No paths found")
+		os.Exit(1)
+	}
+	turns, _, err := PlanTurns(graph, paths, Stops(graph, *roundTrip))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		if out, err = os.Create(*outPath); err != nil {
+			fmt.Println("can't create the SVG file")
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	if err := WriteSVG(out, graph, turns, *width, *height, *turnSeconds); err != nil {
+		fmt.Println("can't write the SVG file")
+		os.Exit(1)
+	}
+}
+
+// WriteSVG writes a standalone SVG of the graph with every ant animated through its
+// rooms, one turn every turnSeconds, using SMIL so no script is needed to play it.
+func WriteSVG(w io.Writer, graph *Graph, turns [][]Move, width, height int, turnSeconds float64) error {
+	radius := roomRadius(len(graph.Rooms), width, height)
+	positions := Layout(graph, float64(width), float64(height), float64(radius+4))
+	names := sortedRooms(graph)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	fmt.Fprintf(out, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+
+	fmt.Fprintln(out, "<g stroke=\"black\" stroke-width=\"1\">")
+	for _, name := range names {
+		for neighbor := range graph.Rooms[name].Edges {
+			if name < neighbor {
+				from, to := positions[name], positions[neighbor]
+				fmt.Fprintf(out, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\"/>\n", from.X, from.Y, to.X, to.Y)
+			}
+		}
+	}
+	fmt.Fprintln(out, "</g>")
+
+	fmt.Fprintf(out, "<g stroke=\"black\" font-family=\"sans-serif\" font-size=\"%d\" text-anchor=\"middle\">\n", radius+2)
+	for _, name := range names {
+		fill := "#ffffe0"
+		switch name {
+		case graph.Start:
+			fill = "#00a000"
+		case graph.End:
+			fill = "#0000c8"
+		}
+		p := positions[name]
+		fmt.Fprintf(out, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%d\" fill=\"%s\"/>\n", p.X, p.Y, radius, fill)
+		fmt.Fprintf(out, "<text x=\"%.1f\" y=\"%.1f\" stroke=\"none\">%s</text>\n", p.X, p.Y-float64(radius)-2, html.EscapeString(name))
+	}
+	fmt.Fprintln(out, "</g>")
+
+	// Room of every ant after each turn, starting from the start room.
+	tracks := make(map[int][]string)
+	for t, turn := range turns {
+		for _, move := range turn {
+			track := tracks[move.Ant]
+			for len(track) < t+1 {
+				if len(track) == 0 {
+					track = append(track, graph.Start)
+				} else {
+					track = append(track, track[len(track)-1])
+				}
+			}
+			tracks[move.Ant] = append(track, move.Room)
+		}
+	}
+	ants := make([]int, 0, len(tracks))
+	for ant := range tracks {
+		ants = append(ants, ant)
+	}
+	sort.Ints(ants)
+
+	duration := float64(len(turns)) * turnSeconds
+	fmt.Fprintln(out, "<g fill=\"red\">")
+	for _, ant := range ants {
+		track := tracks[ant]
+		for len(track) <= len(turns) {
+			track = append(track, track[len(track)-1])
+		}
+		var keyTimes, xs, ys []string
+		for t, room := range track {
+			// Keep the first and last turn and every turn where the ant moves or starts to.
+			if t > 0 && t < len(track)-1 && track[t-1] == room && track[t+1] == room {
+				continue
+			}
+			keyTimes = append(keyTimes, fmt.Sprintf("%.4f", float64(t)/float64(len(turns))))
+			xs = append(xs, fmt.Sprintf("%.1f", positions[room].X))
+			ys = append(ys, fmt.Sprintf("%.1f", positions[room].Y))
+		}
+		fmt.Fprintf(out, "<circle r=\"%d\" cx=\"%s\" cy=\"%s\"><title>L%d</title>\n", (radius+1)/2, xs[0], ys[0], ant)
+		fmt.Fprintf(out, "<animate attributeName=\"cx\" dur=\"%.2fs\" repeatCount=\"indefinite\" keyTimes=\"%s\" values=\"%s\"/>\n", duration, strings.Join(keyTimes, ";"), strings.Join(xs, ";"))
+		fmt.Fprintf(out, "<animate attributeName=\"cy\" dur=\"%.2fs\" repeatCount=\"indefinite\" keyTimes=\"%s\" values=\"%s\"/>\n", duration, strings.Join(keyTimes, ";"), strings.Join(ys, ";"))
+		fmt.Fprintln(out, "</circle>")
+	}
+	fmt.Fprintln(out, "</g>")
+	fmt.Fprintln(out, "</svg>")
+	return out.Flush()
+}
@@ -0,0 +1,91 @@
+package lemin
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SweepResult is the best solution found for one ant count.
+type SweepResult struct {
+	Ants  int `json:"ants"`
+	Turns int `json:"turns"`
+	Paths int `json:"paths"`
+}
+
+// RunSweep implements the `sweep` command.
+func RunSweep(args []string) {
+	flags := flag.NewFlagSet("sweep", flag.ExitOnError)
+	antRange := flags.String("ants", "", "range of ant counts to solve for, as FROM..TO")
+	format := flags.String("format", "csv", "output format: csv or json")
+	flags.Parse(args)
+	from, to, err := parseRange(*antRange)
+	if flags.NArg() != 1 || err != nil || (*format != "csv" && *format != "json") {
+		fmt.Println("Usage: program sweep --ants=FROM..TO [--format=csv|json] input_file")
+		os.Exit(1)
+	}
+
+	graph, _, err := ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	results := Sweep(graph, from, to)
+	if results == nil {
+		fmt.Println("No paths found")
+		os.Exit(1)
+	}
+
+	if *format == "json" {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+	fmt.Println("ants,turns,paths")
+	for _, result := range results {
+		fmt.Printf("%d,%d,%d\n", result.Ants, result.Turns, result.Paths)
+	}
+}
+
+// Sweep solves the graph for every ant count in [from, to]. The Suurballe iterations are
+// run once for the largest count and their path sets are reused for every smaller one.
+func Sweep(graph *Graph, from, to int) []SweepResult {
+	clone := CloneGraph(graph)
+	ReduceGraph(clone)
+	candidates := CandidatePaths(clone, to)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	results := make([]SweepResult, 0, to-from+1)
+	for ants := from; ants <= to; ants++ {
+		paths, steps := BestPaths(candidates, ants)
+		best := *paths // The candidates are shared by every count, so count the steps on a copy
+		best.TotalSteps = steps
+		used, _ := paths.usedPaths(ants) // Longer paths of the set may get no ant
+		results = append(results, SweepResult{Ants: ants, Turns: best.Turns(), Paths: used})
+	}
+	return results
+}
+
+// parseRange parses "FROM..TO" or a single count into an inclusive range of positive counts.
+func parseRange(s string) (int, int, error) {
+	bounds := strings.SplitN(s, "..", 2)
+	from, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	to := from
+	if len(bounds) == 2 {
+		if to, err = strconv.Atoi(bounds[1]); err != nil {
+			return 0, 0, err
+		}
+	}
+	if from < 1 || to < from {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,28 @@
+package lemin
+
+import "testing"
+
+func TestSweepMatchesSolve(t *testing.T) {
+	for _, file := range []string{"example00.txt", "example01.txt", "example02.txt", "example03.txt", "example04.txt", "example05.txt"} {
+		graph, _, err := ReadFile("../lemin_test/audit/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, result := range Sweep(graph, 1, 30) {
+			clone := CloneGraph(graph)
+			clone.Ants = result.Ants
+			paths := Solve(clone)
+			turns := len(Schedule(paths, result.Ants))
+			used := 0
+			for _, ants := range paths.Assignment {
+				if ants > 0 {
+					used++
+				}
+			}
+			if result.Turns != turns || result.Paths != used {
+				t.Errorf("%s with %d ants: sweep says %d turns on %d paths, solving takes %d turns on %d paths",
+					file, result.Ants, result.Turns, result.Paths, turns, used)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package lemin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AntTrace is the journey of one ant: the path it was sent along, the turns it
+// left the start room and reached the last stop, and every room it went through.
+type AntTrace struct {
+	Ant       int
+	Path      int // Index in Paths.AllPaths, -1 when the ant left along none of them
+	Departure int // Turn it starts through its first tunnel, counting from 1, or 0 if it never moved
+	Arrival   int // Turn of its last move, into the last stop, or 0 if it is stranded
+	Rooms     []string
+}
+
+// Travel returns the number of turns the ant spent between the start room and the
+// last stop, or 0 if it never reached it.
+func (trace AntTrace) Travel() int {
+	if trace.Arrival == 0 {
+		return 0
+	}
+	return trace.Arrival - trace.Departure + 1
+}
+
+// Wait returns the number of turns the ant spent in the start room before leaving.
+func (trace AntTrace) Wait() int {
+	if trace.Departure == 0 {
+		return 0
+	}
+	return trace.Departure - 1
+}
+
+// TraceAnts returns the journey of each ant through the turns to the last of the
+// stops, ordered by ant number.
+func TraceAnts(graph *Graph, paths *Paths, turns [][]Move, stops []string) []AntTrace {
+	antPath := antPaths(graph, paths, turns)
+	traces := make([]AntTrace, graph.Ants)
+	for i := range traces {
+		traces[i] = AntTrace{Ant: i + 1, Path: antPath[i], Rooms: []string{graph.Start}}
+	}
+	for t, moves := range turns {
+		for _, move := range moves {
+			trace := &traces[move.Ant-1]
+			if trace.Departure == 0 {
+				trace.Departure = t + 2 - graph.Speed(move.Ant) // A slow ant starts a turn before it moves
+			}
+			trace.Rooms = append(trace.Rooms, move.Room)
+			trace.Arrival = t + 1
+		}
+	}
+	for i := range traces {
+		if trace := &traces[i]; trace.Rooms[len(trace.Rooms)-1] != stops[len(stops)-1] || trace.Departure == 0 {
+			trace.Arrival = 0
+		}
+	}
+	return traces
+}
+
+// antPaths returns the index of the path each ant left the start room along, by ant
+// number - 1, or -1 for an ant that didn't. Paths only share the start and end rooms,
+// so the first room an ant enters tells its path.
+func antPaths(graph *Graph, paths *Paths, turns [][]Move) []int {
+	pathOf := make(map[string]int, paths.NumPaths)
+	for i, path := range paths.AllPaths {
+		pathOf[path.Front().Next().Value.(string)] = i
+	}
+	antPath := make([]int, graph.Ants)
+	moved := make([]bool, graph.Ants)
+	for i := range antPath {
+		antPath[i] = -1
+	}
+	for _, moves := range turns {
+		for _, move := range moves {
+			if !moved[move.Ant-1] {
+				moved[move.Ant-1] = true
+				if i, ok := pathOf[move.Room]; ok {
+					antPath[move.Ant-1] = i
+				}
+			}
+		}
+	}
+	return antPath
+}
+
+// pathAnts returns the number of ants that left along each path.
+func pathAnts(paths *Paths, antPath []int) []int {
+	counts := make([]int, paths.NumPaths)
+	for _, i := range antPath {
+		if i >= 0 {
+			counts[i]++
+		}
+	}
+	return counts
+}
+
+// PrintTraces prints the journey of every ant followed by travel and waiting statistics.
+// Turns an ant doesn't have are shown as "-", and travel times only count the ants that
+// reach the last stop and waiting times the ants that leave the start room.
+func PrintTraces(traces []AntTrace) {
+	fmt.Printf("%-8s %5s %8s %8s  %s\n", "Ant", "Path", "Departs", "Arrives", "Rooms")
+	var travel, wait []int
+	for _, trace := range traces {
+		fmt.Printf("%-8s %5s %8s %8s  %s\n", fmt.Sprintf("L%d", trace.Ant), orDash(trace.Path+1),
+			orDash(trace.Departure), orDash(trace.Arrival), strings.Join(trace.Rooms, " "))
+		if trace.Departure > 0 {
+			wait = append(wait, trace.Wait())
+		}
+		if trace.Arrival > 0 {
+			travel = append(travel, trace.Travel())
+		}
+	}
+	if len(wait) == 0 {
+		return
+	}
+
+	fmt.Println()
+	if len(travel) > 0 {
+		printStats("Travel time", travel)
+	}
+	printStats("Waiting at start", wait)
+}
+
+// orDash formats a turn or path number, with 0 shown as "-".
+func orDash(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.Itoa(n)
+}
+
+// printStats prints the mean, median and maximum of values, in turns.
+func printStats(name string, values []int) {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	sum := 0
+	for _, v := range sorted {
+		sum += v
+	}
+	mid := len(sorted) / 2
+	median := float64(sorted[mid])
+	if len(sorted)%2 == 0 {
+		median = float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+	fmt.Printf("%-17s mean %.2f, median %.1f, max %d turns\n", name+":",
+		float64(sum)/float64(len(sorted)), median, sorted[len(sorted)-1])
+}
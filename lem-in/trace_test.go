@@ -0,0 +1,48 @@
+package lemin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTraceAnts(t *testing.T) {
+	const chain = "##start\ns 0 0\na 1 0\nb 2 0\n##end\ne 3 0\ns-a\na-b\nb-e\n"
+	tests := []struct {
+		name    string
+		content string
+		traces  []AntTrace
+	}{
+		{"chain", "2\n" + chain, []AntTrace{
+			{1, 0, 1, 3, []string{"s", "a", "b", "e"}},
+			{2, 0, 2, 4, []string{"s", "a", "b", "e"}},
+		}},
+		{"slow ant", "1\n" + chain + "##slow 1\n", []AntTrace{
+			{1, 0, 1, 6, []string{"s", "a", "b", "e"}},
+		}},
+		{"stranded ants", "2\n" + chain + "##close b-e at 3\n", []AntTrace{
+			{1, 0, 1, 0, []string{"s", "a", "b"}},
+			{2, 0, 2, 0, []string{"s", "a"}},
+		}},
+	}
+	for _, test := range tests {
+		graph, _, err := ParseMap(test.content)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		paths := Solve(graph)
+		stops := Stops(graph, false)
+		turns, _, err := PlanTurns(graph, paths, stops)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		traces := TraceAnts(graph, paths, turns, stops)
+		if !reflect.DeepEqual(traces, test.traces) {
+			t.Errorf("%s: %v, want %v", test.name, traces, test.traces)
+		}
+		for _, trace := range traces {
+			if trace.Arrival == 0 && trace.Travel() != 0 {
+				t.Errorf("%s: stranded L%d travels for %d turns", test.name, trace.Ant, trace.Travel())
+			}
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package lemin
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Width of the side panel listing the paths, in columns.
+const tuiPanelWidth = 32
+
+// RunTUI shows the turns in the terminal and steps through them with the keyboard.
+func RunTUI(graph *Graph, paths *Paths, turns [][]Move) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("can't open the terminal")
+	}
+	defer tty.Close()
+	restore, err := rawTerminal(tty)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	rows, cols := terminalSize(tty)
+	screen := newTUIScreen(graph, paths, turns, rows, cols)
+	out := bufio.NewWriter(tty)
+	fmt.Fprint(out, "\x1b[?25l") // Hide the cursor
+	defer func() {
+		fmt.Fprint(out, "\x1b[?25h\x1b[H\x1b[2J")
+		out.Flush()
+	}()
+
+	turn := 0
+	keys := make([]byte, 8)
+	for {
+		fmt.Fprint(out, screen.render(turns, turn))
+		out.Flush()
+
+		n, err := tty.Read(keys)
+		if err != nil {
+			return nil
+		}
+		switch key := string(keys[:n]); key {
+		case "q", "\x1b":
+			return nil
+		case "n", " ", "l", "\x1b[C":
+			if turn < len(turns) {
+				turn++
+			}
+		case "p", "h", "\x1b[D":
+			if turn > 0 {
+				turn--
+			}
+		case "r":
+			turn = 0
+		case "e":
+			turn = len(turns)
+		}
+	}
+}
+
+// tuiScreen draws the rooms on a character grid next to a panel listing the paths.
+type tuiScreen struct {
+	graph      *Graph
+	paths      *Paths
+	pathAnts   []int       // Number of ants that left along each path
+	lastMove   map[int]int // Turn each ant makes its last move in
+	rows, cols int
+	cells      map[string][2]int // Row and column of each room
+	background [][]rune
+}
+
+func newTUIScreen(graph *Graph, paths *Paths, turns [][]Move, rows, cols int) *tuiScreen {
+	screen := &tuiScreen{graph: graph, paths: paths, rows: rows, cols: cols, cells: make(map[string][2]int)}
+	screen.pathAnts = pathAnts(paths, antPaths(graph, paths, turns))
+	screen.lastMove = make(map[int]int)
+	for t, moves := range turns {
+		for _, move := range moves {
+			screen.lastMove[move.Ant] = t + 1
+		}
+	}
+	gridW, gridH := cols-tuiPanelWidth, rows-2
+	if gridW < 10 {
+		gridW = 10
+	}
+	if gridH < 5 {
+		gridH = 5
+	}
+	// Cells are about twice as tall as wide, so lay out on half the width and stretch.
+	for name, p := range Layout(graph, float64(gridW-1)/2, float64(gridH-1), 1) {
+		screen.cells[name] = [2]int{int(math.Round(p.Y)), int(math.Round(2 * p.X))}
+	}
+
+	screen.background = make([][]rune, gridH)
+	for r := range screen.background {
+		screen.background[r] = []rune(strings.Repeat(" ", gridW))
+	}
+	for _, name := range sortedRooms(graph) {
+		for neighbor := range graph.Rooms[name].Edges {
+			if name < neighbor {
+				screen.plotLine(screen.cells[name], screen.cells[neighbor])
+			}
+		}
+	}
+	for name, cell := range screen.cells {
+		mark := 'o'
+		switch name {
+		case graph.Start:
+			mark = 'S'
+		case graph.End:
+			mark = 'E'
+		}
+		screen.background[cell[0]][cell[1]] = mark
+	}
+	return screen
+}
+
+// plotLine draws a tunnel between two cells with dots.
+func (screen *tuiScreen) plotLine(from, to [2]int) {
+	steps := int(math.Max(math.Abs(float64(to[0]-from[0])), math.Abs(float64(to[1]-from[1]))))
+	for i := 1; i < steps; i++ {
+		r := from[0] + int(math.Round(float64((to[0]-from[0])*i)/float64(steps)))
+		c := from[1] + int(math.Round(float64((to[1]-from[1])*i)/float64(steps)))
+		if screen.background[r][c] == ' ' {
+			screen.background[r][c] = '.'
+		}
+	}
+}
+
+// render returns the escape sequences drawing the screen after the given number of turns.
+func (screen *tuiScreen) render(turns [][]Move, turn int) string {
+	antRooms := make(map[int]string)
+	for _, moves := range turns[:turn] {
+		for _, move := range moves {
+			antRooms[move.Ant] = move.Room
+		}
+	}
+	inRoom := make(map[string]int)
+	finished := 0
+	for ant, room := range antRooms {
+		if turn >= screen.lastMove[ant] {
+			finished++
+		} else if room != screen.graph.Start && room != screen.graph.End {
+			inRoom[room] = ant
+		}
+	}
+
+	// Overlay ant numbers on the rooms they're in, highlighted.
+	grid := make([][]rune, len(screen.background))
+	highlight := make([][]bool, len(screen.background))
+	for r, row := range screen.background {
+		grid[r] = append([]rune(nil), row...)
+		highlight[r] = make([]bool, len(row))
+	}
+	for room, ant := range inRoom {
+		cell := screen.cells[room]
+		for i, digit := range strconv.Itoa(ant) {
+			if c := cell[1] + i; c < len(grid[cell[0]]) {
+				grid[cell[0]][c] = digit
+				highlight[cell[0]][c] = true
+			}
+		}
+	}
+
+	panel := screen.panel()
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	for r, row := range grid {
+		for c, ch := range row {
+			if highlight[r][c] {
+				b.WriteString("\x1b[1;31m" + string(ch) + "\x1b[0m")
+			} else {
+				b.WriteRune(ch)
+			}
+		}
+		if r < len(panel) {
+			b.WriteString(" " + panel[r])
+		}
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "Turn %d/%d  Finished %d/%d  [n/→] next  [p/←] back  [r] restart  [e] end  [q] quit",
+		turn, len(turns), finished, screen.graph.Ants)
+	return b.String()
+}
+
+// panel lists each path with its length and the number of ants sent along it.
+func (screen *tuiScreen) panel() []string {
+	lines := []string{fmt.Sprintf("%d paths:", screen.paths.NumPaths)}
+	for i, path := range screen.paths.AllPaths {
+		lines = append(lines, fmt.Sprintf("#%-3d %4d rooms %6d ants", i+1, path.Len(), screen.pathAnts[i]))
+	}
+	return lines
+}
+
+// rawTerminal switches the terminal to unbuffered input without echo and returns a
+// function restoring its previous settings.
+func rawTerminal(tty *os.File) (func(), error) {
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("can't read the terminal settings")
+	}
+	if _, err := stty(tty, "cbreak", "-echo"); err != nil {
+		return nil, fmt.Errorf("can't configure the terminal")
+	}
+	return func() { stty(tty, strings.TrimSpace(saved)) }, nil
+}
+
+// terminalSize returns the number of rows and columns of the terminal, or 24x80 if unknown.
+func terminalSize(tty *os.File) (int, int) {
+	out, err := stty(tty, "size")
+	if fields := strings.Fields(out); err == nil && len(fields) == 2 {
+		rows, errRows := strconv.Atoi(fields[0])
+		cols, errCols := strconv.Atoi(fields[1])
+		if errRows == nil && errCols == nil && rows > 0 && cols > 0 {
+			return rows, cols
+		}
+	}
+	return 24, 80
+}
+
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	return string(out), err
+}
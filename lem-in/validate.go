@@ -0,0 +1,194 @@
+package lemin
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// RunValidate implements the `validate` command, checking the turns printed for a map,
+// read from the output file or from the standard input.
+func RunValidate(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	roundTrip := flags.Bool("round-trip", false, "the ants go back to the start room from the end room")
+	flags.Parse(args)
+	if flags.NArg() != 1 && flags.NArg() != 2 {
+		fmt.Println("Usage: program validate [--round-trip] input_file [output_file]")
+		os.Exit(1)
+	}
+	graph, content, err := ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	var output []byte
+	if flags.NArg() == 2 {
+		output, err = os.ReadFile(flags.Arg(1))
+	} else {
+		output, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	turns, err := parseTurns(string(output), content)
+	if err == nil {
+		err = ValidateTurns(graph, turns, Stops(graph, *roundTrip))
+	}
+	if err != nil {
+		fmt.Println("Invalid: " + err.Error())
+		os.Exit(1)
+	}
+	fmt.Printf("OK: %d ants in %d turns\n", graph.Ants, len(turns))
+}
+
+// ValidateTurns checks that the turns take every ant of the graph through the stops, in
+// order, by the rules: ants go through existing tunnels, one ant per tunnel and per room
+// other than the start and end rooms at a time, no ant enters a stop before the ones
+// before it, slow ants spend two turns in each tunnel, closed tunnels and failed rooms
+// aren't used from the turn of their event, and no ant without priority reaches the
+// last stop before an ant with priority.
+func ValidateTurns(graph *Graph, turns [][]Move, stops []string) error {
+	closed := make(map[string]int) // Turn each tunnel closes in
+	failed := make(map[string]int) // Turn each room fails in
+	for _, event := range graph.Events {
+		switch key := tunnelKey(event.A, event.B); event.Kind {
+		case EventClose:
+			if turn, ok := closed[key]; !ok || event.Turn < turn {
+				closed[key] = event.Turn
+			}
+		case EventFail:
+			if turn, ok := failed[event.A]; !ok || event.Turn < turn {
+				failed[event.A] = event.Turn
+			}
+		}
+	}
+
+	depots := map[string]bool{graph.Start: true, graph.End: true}
+	goal := stops[len(stops)-1]
+
+	rooms := make([]string, graph.Ants+1)    // Room of each ant, by number
+	reached := make([]int, graph.Ants+1)     // Index of the last stop each ant went through
+	lastMove := make([]int, graph.Ants+1)    // Turn of the last move of each ant
+	holder := make(map[string]int)           // Ant in each room other than start and end
+	crossed := make(map[string]map[int]bool) // Turns each tunnel is crossed in
+	for ant := range rooms {
+		rooms[ant] = graph.Start
+	}
+	lastPriority, firstRegular := 0, 0
+
+	for t, turn := range turns {
+		t++
+		moved := make(map[int]bool)
+		for _, move := range turn {
+			if move.Ant < 1 || move.Ant > graph.Ants {
+				return fmt.Errorf("turn %d: there's no ant %d", t, move.Ant)
+			}
+			if moved[move.Ant] {
+				return fmt.Errorf("turn %d: L%d moves twice", t, move.Ant)
+			}
+			moved[move.Ant] = true
+			from := rooms[move.Ant]
+			if reached[move.Ant] == len(stops)-1 {
+				return fmt.Errorf("turn %d: L%d moves after reaching %s for good", t, move.Ant, goal)
+			}
+			if node := graph.Rooms[from]; node == nil || node.Edges[move.Room] == 0 {
+				return fmt.Errorf("turn %d: L%d moves from %s to %s without a tunnel", t, move.Ant, from, move.Room)
+			}
+
+			// A slow ant enters the tunnel the turn before it reaches the next room.
+			speed := graph.Speed(move.Ant)
+			if t-speed < lastMove[move.Ant] {
+				return fmt.Errorf("turn %d: L%d is slow and can't reach %s yet", t, move.Ant, move.Room)
+			}
+			key := tunnelKey(from, move.Room)
+			if crossed[key] == nil {
+				crossed[key] = make(map[int]bool)
+			}
+			for crossing := t - speed + 1; crossing <= t; crossing++ {
+				if crossed[key][crossing] {
+					return fmt.Errorf("turn %d: tunnel %s is crossed by more than one ant", crossing, key)
+				}
+				if turn, ok := closed[key]; ok && crossing >= turn {
+					return fmt.Errorf("turn %d: L%d goes through %s, closed at turn %d", crossing, move.Ant, key, turn)
+				}
+				crossed[key][crossing] = true
+			}
+			if turn, ok := failed[move.Room]; ok && t >= turn {
+				return fmt.Errorf("turn %d: L%d enters %s, failed at turn %d", t, move.Ant, move.Room, turn)
+			}
+
+			if holder[from] == move.Ant {
+				delete(holder, from)
+			}
+			rooms[move.Ant] = move.Room
+			lastMove[move.Ant] = t
+			if next := reached[move.Ant] + 1; move.Room == stops[next] {
+				reached[move.Ant]++
+			} else {
+				for _, room := range laterStops(stops, next) {
+					if move.Room == room {
+						return fmt.Errorf("turn %d: L%d enters %s before %s", t, move.Ant, room, stops[next])
+					}
+				}
+			}
+			if reached[move.Ant] == len(stops)-1 {
+				if graph.Priority[move.Ant] {
+					lastPriority = t
+				} else if firstRegular == 0 {
+					firstRegular = t
+				}
+			}
+		}
+
+		// Rooms are checked once every ant has moved, as an ant can follow another.
+		for _, move := range turn {
+			if depots[move.Room] {
+				continue
+			}
+			if ant, ok := holder[move.Room]; ok && ant != move.Ant {
+				return fmt.Errorf("turn %d: L%d and L%d are both in %s", t, ant, move.Ant, move.Room)
+			}
+			holder[move.Room] = move.Ant
+		}
+	}
+
+	for ant := 1; ant <= graph.Ants; ant++ {
+		if reached[ant] < len(stops)-1 {
+			return fmt.Errorf("L%d doesn't reach %s", ant, stops[reached[ant]+1])
+		}
+	}
+	if firstRegular > 0 && firstRegular < lastPriority {
+		return fmt.Errorf("an ant without priority arrives at turn %d, before the last ant with priority at turn %d", firstRegular, lastPriority)
+	}
+	return nil
+}
+
+// parseTurns returns the moves of each turn of the output, which may repeat the map
+// before them. A slow ant can leave a turn without moves, so empty lines are turns too.
+func parseTurns(output, content string) ([][]Move, error) {
+	if strings.HasPrefix(output, content+"\n\n") {
+		output = strings.TrimPrefix(output, content+"\n\n")
+	} else if i := strings.Index(output, "\n\n"); i >= 0 && !strings.HasPrefix(output, "L") {
+		output = output[i+2:] // The turns of a map written differently
+	}
+	var turns [][]Move
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		// Anything after the turns, like a count of stranded ants, isn't a move.
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "L") {
+			break
+		}
+		moves, err := ParseMoves(line)
+		if err != nil {
+			return nil, err
+		}
+		turns = append(turns, moves[0])
+	}
+	for len(turns) > 0 && len(turns[len(turns)-1]) == 0 {
+		turns = turns[:len(turns)-1]
+	}
+	return turns, nil
+}
@@ -0,0 +1,50 @@
+package lemin
+
+import "testing"
+
+func TestValidateTurns(t *testing.T) {
+	const rooms = "##start\ns 0 0\na 1 0\nc 2 1\n##end\ne 2 0\n"
+	const tunnels = "s-a\na-e\ns-b\nb-c\nc-e\na-c\n"
+	const twoAnts = "2\n" + rooms + "b 1 1\n" + tunnels
+	const pickup = "2\n" + rooms + "##pickup\nb 1 1\n" + tunnels
+	const oneAnt = "1\n##start\ns 0 0\na 1 0\n##end\ne 2 0\ns-a\na-e\n"
+	tests := []struct {
+		name      string
+		content   string
+		roundTrip bool
+		output    string
+		err       string
+	}{
+		{"valid", twoAnts, false, "L1-a L2-b\nL1-e L2-c\nL2-e\n", ""},
+		{"unknown ant", twoAnts, false, "L3-a\n", "turn 1: there's no ant 3"},
+		{"ant moving twice", twoAnts, false, "L1-a L1-e\n", "turn 1: L1 moves twice"},
+		{"ant moving after the end", twoAnts, false, "L1-a L2-b\nL1-e L2-c\nL1-a L2-e\n", "turn 3: L1 moves after reaching e for good"},
+		{"no tunnel", twoAnts, false, "L1-c\n", "turn 1: L1 moves from s to c without a tunnel"},
+		{"tunnel shared", twoAnts, false, "L1-a L2-a\n", "turn 1: tunnel a-s is crossed by more than one ant"},
+		{"room shared", twoAnts, false, "L1-a L2-b\nL1-c L2-c\n", "turn 2: L1 and L2 are both in c"},
+		{"ant left behind", twoAnts, false, "L1-a L2-b\nL1-e\n", "L2 doesn't reach e"},
+		{"slow ant", twoAnts + "##slow 1\n", false, "L1-a\n", "turn 1: L1 is slow and can't reach a yet"},
+		{"slow ant on time", twoAnts + "##slow 1\n", false, "L2-b\nL1-a L2-c\n\nL1-e L2-e\n", ""},
+		{"closed tunnel", twoAnts + "##close a-e at 2\n", false, "L1-a\nL1-e\n", "turn 2: L1 goes through a-e, closed at turn 2"},
+		{"failed room", twoAnts + "##fail a at 1\n", false, "L1-a\n", "turn 1: L1 enters a, failed at turn 1"},
+		{"priority", twoAnts + "##priority 2\n", false, "L1-a L2-b\nL1-e L2-c\nL2-e\n", "an ant without priority arrives at turn 2, before the last ant with priority at turn 3"},
+		{"pickup", pickup, false, "L1-b L2-a\nL1-c L2-s\nL1-e L2-b\nL2-c\nL2-e\n", ""},
+		{"pickup shared", pickup, false, "L1-b\nL2-b\n", "turn 2: L1 and L2 are both in b"},
+		{"pickup skipped", pickup, false, "L1-a\nL1-e\n", "turn 2: L1 enters e before b"},
+		{"round trip", oneAnt, true, "L1-a\nL1-e\nL1-a\nL1-s\n", ""},
+		{"round trip cut short", oneAnt, true, "L1-a\nL1-e\n", "L1 doesn't reach s"},
+	}
+	for _, test := range tests {
+		graph, content, err := ParseMap(test.content)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		turns, err := parseTurns(test.output, content)
+		if err == nil {
+			err = ValidateTurns(graph, turns, Stops(graph, test.roundTrip))
+		}
+		if (err == nil) != (test.err == "") || (err != nil && err.Error() != test.err) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}